@@ -6,26 +6,75 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"sort"
+	"strings"
 
-	"github.com/rs/zerolog/log"
 	"gopkg.in/yaml.v3"
 
 	"github.com/MashinaMashina/api-tests/test"
+	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
 )
 
 // Find ищет все тесты в папке.
 // Обходит так же вложенные директории.
-func Find(dir, namePrefix string) []test.Group {
+// Файлы, которые не удалось прочитать или разобрать, пропускаются,
+// а ошибки по ним возвращаются вторым значением.
+func Find(dir, namePrefix string) ([]test.Group, []error) {
+	return find(dir, namePrefix, test.Init{}, nil)
+}
+
+// find ищет тесты в папке.
+// parent - init родительской папки, его переменные наследуются вложенными группами.
+// parentRefs - файлы, на которые ссылаются setup и teardown родительских папок.
+func find(dir, namePrefix string, parent test.Init, parentRefs map[string]struct{}) ([]test.Group, []error) {
 	files, err := ioutil.ReadDir(dir)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	group := test.Group{
 		Name: namePrefix,
+		Init: inheritInit(parent),
+	}
+
+	var (
+		groups []test.Group
+		errs   []error
+		// Файлы, на которые ссылаются setup и teardown, не являются тестами группы,
+		// в том числе если лежат во вложенной папке
+		refs = make(map[string]struct{}, len(parentRefs))
+	)
+
+	for path := range parentRefs {
+		refs[path] = struct{}{}
+	}
+
+	// init файл разбираем до остальных, его переменные нужны вложенным папкам
+	for _, file := range files {
+		if file.IsDir() || !isInit(file.Name()) {
+			continue
+		}
+
+		path := dir + "/" + file.Name()
+
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading init config '%s': %w", path, err))
+			continue
+		}
+
+		init, initRefs, err := parseInit(bytes, dir, file.Name())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decoding init config '%s': %w", path, err))
+			continue
+		}
+
+		group.Init = mergeInit(group.Init, init)
+		for path := range initRefs {
+			refs[path] = struct{}{}
+		}
 	}
 
-	var groups []test.Group
 	for _, file := range files {
 		// Временный файл
 		if file.Name()[0] == '~' {
@@ -37,7 +86,9 @@ func Find(dir, namePrefix string) []test.Group {
 		ext := filepath.Ext(file.Name())
 
 		if file.IsDir() {
-			groups = append(groups, Find(path, namePrefix+suffix)...)
+			subGroups, subErrs := find(path, namePrefix+suffix, group.Init, refs)
+			groups = append(groups, subGroups...)
+			errs = append(errs, subErrs...)
 			continue
 		}
 
@@ -46,34 +97,38 @@ func Find(dir, namePrefix string) []test.Group {
 			continue
 		}
 
-		bytes, err := ioutil.ReadFile(path)
-		if err != nil {
-			log.Error().Str("file", path).Err(err).Msg("reading test case")
+		if isInit(file.Name()) {
 			continue
 		}
 
-		if file.Name() == "init.yaml" || file.Name() == "init.yml" {
-			init, err := parseInit(bytes)
-			if err != nil {
-				log.Error().Str("file", path).Err(err).Msg("decoding init config")
-				continue
-			}
-
-			group.Init = init
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading test case '%s': %w", path, err))
 			continue
 		}
 
-		testcase, err := parseCase(bytes)
+		testcase, err := parseCase(bytes, dir)
 		if err != nil {
-			log.Error().Str("file", path).Err(err).Msg("decoding test case")
+			errs = append(errs, fmt.Errorf("decoding test case '%s': %w", path, err))
 			continue
 		}
 
 		testcase.Filename = file.Name()
+		testcase.Dir = dir
 
 		group.Tests = append(group.Tests, testcase)
 	}
 
+	if len(refs) > 0 {
+		tests := group.Tests[:0]
+		for _, testcase := range group.Tests {
+			if _, ok := refs[filepath.Join(dir, testcase.Filename)]; !ok {
+				tests = append(tests, testcase)
+			}
+		}
+		group.Tests = tests
+	}
+
 	if len(group.Tests) != 0 {
 		// Сортируем тесты по алфавиту
 		sort.Slice(group.Tests, func(i, j int) bool {
@@ -83,11 +138,59 @@ func Find(dir, namePrefix string) []test.Group {
 		groups = append(groups, group)
 	}
 
-	return groups
+	return groups, errs
+}
+
+func isInit(filename string) bool {
+	return filename == "init.yaml" || filename == "init.yml"
+}
+
+// inheritInit возвращает настройки родительской папки, которые действуют на вложенные группы:
+// переменные, continue-on-failure и openapi. Setup, teardown и global не наследуются.
+func inheritInit(parent test.Init) test.Init {
+	init := test.Init{
+		ContinueOnFailure: parent.ContinueOnFailure,
+		OpenAPI:           parent.OpenAPI,
+	}
+
+	if len(parent.Store) > 0 {
+		init.Store = make(map[string]interface{}, len(parent.Store))
+		for k, v := range parent.Store {
+			init.Store[k] = v
+		}
+	}
+
+	return init
 }
 
-// parseCase разбирает отдельный тест
-func parseCase(b []byte) (test.Case, error) {
+// mergeInit дополняет унаследованные настройки настройками init файла папки.
+// Переменные папки перекрывают одноименные переменные родителя.
+func mergeInit(base, init test.Init) test.Init {
+	if len(base.Store) > 0 {
+		store := make(map[string]interface{}, len(base.Store)+len(init.Store))
+		for k, v := range base.Store {
+			store[k] = v
+		}
+		for k, v := range init.Store {
+			store[k] = v
+		}
+		init.Store = store
+	}
+
+	if init.ContinueOnFailure == nil {
+		init.ContinueOnFailure = base.ContinueOnFailure
+	}
+
+	if init.OpenAPI == "" {
+		init.OpenAPI = base.OpenAPI
+	}
+
+	return init
+}
+
+// parseCase разбирает отдельный тест.
+// dir - папка теста, относительно неё указываются пути к файлам в описании.
+func parseCase(b []byte, dir string) (test.Case, error) {
 	var testcase test.Case
 
 	dec := yaml.NewDecoder(bytes.NewReader(b))
@@ -105,19 +208,136 @@ func parseCase(b []byte) (test.Case, error) {
 		testcase.Request.Method = "GET"
 	}
 
+	testcase.Request.BodyFile = resolvePath(testcase.Request.BodyFile, dir)
+	for i := range testcase.Request.Multipart {
+		testcase.Request.Multipart[i].File = resolvePath(testcase.Request.Multipart[i].File, dir)
+	}
+
+	resolveFiles(testcase.Response.Body, dir)
+	resolveFiles(testcase.Message, dir)
+	resolveFiles(testcase.Receive.Filter, dir)
+	resolveFiles(testcase.Retry.Until, dir)
+
 	return testcase, nil
 }
 
-// parseInit разбирает init файл
-func parseInit(b []byte) (test.Init, error) {
-	var init test.Init
+// resolveFiles делает пути к файлам схем и ключей JWT относительными папке теста
+func resolveFiles(descriptions []validators.ValidatorDescr, dir string) {
+	for i := range descriptions {
+		descriptions[i].SchemaFile = resolvePath(descriptions[i].SchemaFile, dir)
+		resolveRuleFiles(descriptions[i].Rules, dir)
+	}
+}
+
+// resolveRuleFiles делает пути к файлам ключей JWT относительными папке теста, в том числе во вложенных правилах
+func resolveRuleFiles(list []rules.Rule, dir string) {
+	for i := range list {
+		if list[i].Verify != nil {
+			list[i].Verify.KeyFile = resolvePath(list[i].Verify.KeyFile, dir)
+			list[i].Verify.JWKSFile = resolvePath(list[i].Verify.JWKSFile, dir)
+		}
+
+		resolveRuleFiles(list[i].Fields, dir)
+	}
+}
+
+// resolvePath возвращает путь относительно папки теста.
+// Пустые и абсолютные пути, а так же пути с переменными не меняются.
+func resolvePath(path, dir string) string {
+	if path == "" || filepath.IsAbs(path) || strings.HasPrefix(path, "{{") {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+// initFile - содержимое init файла.
+// Setup и teardown тесты могут быть описаны прямо в файле, либо ссылкой на yaml файл,
+// поэтому они разбираются отдельно.
+type initFile struct {
+	test.Init `yaml:",inline"`
+	Setup     []yaml.Node `yaml:"setup"`
+	Teardown  []yaml.Node `yaml:"teardown"`
+}
+
+// parseInit разбирает init файл.
+// Вторым значением возвращает пути к файлам, на которые ссылаются setup и teardown.
+func parseInit(b []byte, dir, filename string) (test.Init, map[string]struct{}, error) {
+	var file initFile
 
 	dec := yaml.NewDecoder(bytes.NewReader(b))
 	dec.KnownFields(true)
 
-	if err := dec.Decode(&init); err != nil {
-		return test.Init{}, err
+	if err := dec.Decode(&file); err != nil {
+		return test.Init{}, nil, err
+	}
+
+	init := file.Init
+	refs := make(map[string]struct{})
+
+	if init.OpenAPI != "" && !filepath.IsAbs(init.OpenAPI) {
+		init.OpenAPI = filepath.Join(dir, init.OpenAPI)
+	}
+
+	var err error
+	init.Setup, err = parseInitCases(file.Setup, dir, filename+": setup", refs)
+	if err != nil {
+		return test.Init{}, nil, err
+	}
+
+	init.Teardown, err = parseInitCases(file.Teardown, dir, filename+": teardown", refs)
+	if err != nil {
+		return test.Init{}, nil, err
+	}
+
+	return init, refs, nil
+}
+
+// parseInitCases разбирает список setup или teardown тестов.
+// Строка - путь к файлу теста относительно папки группы, объект - описание теста.
+func parseInitCases(nodes []yaml.Node, dir, section string, refs map[string]struct{}) ([]test.Case, error) {
+	cases := make([]test.Case, 0, len(nodes))
+
+	for i, node := range nodes {
+		var (
+			b        []byte
+			filename string
+			caseDir  = dir
+			err      error
+		)
+
+		switch node.Kind {
+		case yaml.ScalarNode:
+			path := filepath.Join(dir, node.Value)
+			filename = node.Value
+			caseDir = filepath.Dir(path)
+			refs[path] = struct{}{}
+
+			b, err = ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: reading test case: %w", section, i, err)
+			}
+		case yaml.MappingNode:
+			filename = fmt.Sprintf("%s[%d]", section, i)
+
+			b, err = yaml.Marshal(&node)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", section, i, err)
+			}
+		default:
+			return nil, fmt.Errorf("%s[%d]: must be a file name or a test case", section, i)
+		}
+
+		// Пути в тесте из отдельного файла указываются относительно этого файла
+		testcase, err := parseCase(b, caseDir)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: decoding test case: %w", section, i, err)
+		}
+
+		testcase.Filename = filename
+		testcase.Dir = caseDir
+		cases = append(cases, testcase)
 	}
 
-	return init, nil
+	return cases, nil
 }
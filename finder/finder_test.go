@@ -2,6 +2,8 @@ package finder
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -124,10 +126,39 @@ func TestSimple(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:  "С установкой валидатора body - jsonschema из файла",
+			Input: "name: Тест\nresponse:\n  body:\n    - type: jsonschema\n      schema-file: schemas/report.json",
+			Expect: test.Case{
+				Name: "Тест",
+				Response: test.Response{
+					Body: []validators.ValidatorDescr{{
+						Type:       "jsonschema",
+						SchemaFile: "tests/reports/schemas/report.json",
+					}},
+				},
+			},
+		},
+		{
+			Name:  "С телом из файла и multipart",
+			Input: "name: Тест\nrequest:\n  url: /upload\n  method: POST\n  body-file: bodies/user.json\n  multipart:\n    - name: title\n      value: Отчет\n    - name: file\n      file: files/report.pdf",
+			Expect: test.Case{
+				Name: "Тест",
+				Request: test.Request{
+					Method:   "POST",
+					URL:      "/upload",
+					BodyFile: "tests/reports/bodies/user.json",
+					Multipart: []test.Part{
+						{Name: "title", Value: "Отчет"},
+						{Name: "file", File: "tests/reports/files/report.pdf"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, curCase := range testCases {
-		res, err := parseCase([]byte(curCase.Input))
+		res, err := parseCase([]byte(curCase.Input), "tests/reports")
 
 		if curCase.ExpectErr != nil {
 			assert.EqualError(t, err, curCase.ExpectErr.Error(), curCase.Name)
@@ -136,3 +167,118 @@ func TestSimple(t *testing.T) {
 		assert.Equal(t, curCase.Expect, res, curCase.Name)
 	}
 }
+
+func TestInitSetupTeardown(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "delete-user.yml"), []byte("name: Удаление\nrequest:\n  method: DELETE\n  url: /api/users/{{.userId}}"), 0o644)
+	assert.Nil(t, err)
+
+	input := "store:\n  host: localhost\nsetup:\n  - name: Создание\n    request:\n      method: POST\n      url: /api/users\nteardown:\n  - delete-user.yml"
+
+	init, refs, err := parseInit([]byte(input), dir, "init.yml")
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"host": "localhost"}, init.Store)
+	assert.Equal(t, []test.Case{{
+		Filename: "init.yml: setup[0]",
+		Dir:      dir,
+		Name:     "Создание",
+		Request:  test.Request{Method: "POST", URL: "/api/users"},
+	}}, init.Setup)
+	assert.Equal(t, []test.Case{{
+		Filename: "delete-user.yml",
+		Dir:      dir,
+		Name:     "Удаление",
+		Request:  test.Request{Method: "DELETE", URL: "/api/users/{{.userId}}"},
+	}}, init.Teardown)
+	assert.Contains(t, refs, filepath.Join(dir, "delete-user.yml"))
+
+	_, _, err = parseInit([]byte("setup:\n  - [1, 2]"), dir, "init.yml")
+	assert.EqualError(t, err, "init.yml: setup[0]: must be a file name or a test case")
+}
+
+func TestInitSetupFileInSubdir(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := filepath.Join(dir, "fixtures")
+	assert.Nil(t, os.MkdirAll(fixtures, 0o755))
+
+	content := "name: Создание\nrequest:\n  method: POST\n  url: /api/users\n  body-file: user.json\n  multipart:\n    - name: avatar\n      file: avatar.png"
+	assert.Nil(t, os.WriteFile(filepath.Join(fixtures, "create.yml"), []byte(content), 0o644))
+
+	init, _, err := parseInit([]byte("setup:\n  - fixtures/create.yml"), dir, "init.yml")
+	assert.Nil(t, err)
+
+	if assert.Len(t, init.Setup, 1) {
+		setup := init.Setup[0]
+		assert.Equal(t, fixtures, setup.Dir)
+		assert.Equal(t, filepath.Join(fixtures, "user.json"), setup.Request.BodyFile)
+		assert.Equal(t, filepath.Join(fixtures, "avatar.png"), setup.Request.Multipart[0].File)
+	}
+}
+
+func TestFindSetupFileInSubdir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"init.yml":             "setup:\n  - fixtures/create.yml\nteardown:\n  - fixtures/delete.yml",
+		"1-get.yml":            "name: Получение\nrequest:\n  url: /api/users/1",
+		"fixtures/create.yml":  "name: Создание\nrequest:\n  method: POST\n  url: /api/users",
+		"fixtures/delete.yml":  "name: Удаление\nrequest:\n  method: DELETE\n  url: /api/users/1",
+		"fixtures/1-check.yml": "name: Проверка\nrequest:\n  url: /api/check",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	groups, errs := Find(dir, "")
+	assert.Empty(t, errs)
+
+	tests := make(map[string][]string)
+	for _, group := range groups {
+		for _, testcase := range group.Tests {
+			tests[group.Name] = append(tests[group.Name], testcase.Filename)
+		}
+	}
+
+	// Файлы setup и teardown из вложенной папки не запускаются как ее тесты
+	assert.Equal(t, map[string][]string{
+		"":          {"1-get.yml"},
+		"/fixtures": {"1-check.yml"},
+	}, tests)
+}
+
+func TestInheritedInit(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"init.yml":             "store:\n  host: localhost\n  login: ivan\ncontinue-on-failure: true",
+		"a_login/1-login.yml":  "name: Вход\nrequest:\n  url: /login",
+		"auth/init.yml":        "store:\n  login: roman\nglobal: true",
+		"auth/1-auth.yml":      "name: Авторизация\nrequest:\n  url: /auth",
+		"auth/deep/1-deep.yml": "name: Вложенный\nrequest:\n  url: /deep",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	groups, errs := Find(dir, "")
+	assert.Empty(t, errs)
+
+	inits := make(map[string]test.Init)
+	for _, group := range groups {
+		inits[group.Name] = group.Init
+	}
+
+	assert.Equal(t, map[string]interface{}{"host": "localhost", "login": "ivan"}, inits["/a_login"].Store)
+	assert.True(t, *inits["/a_login"].ContinueOnFailure)
+	assert.False(t, inits["/a_login"].Global)
+
+	assert.Equal(t, map[string]interface{}{"host": "localhost", "login": "roman"}, inits["/auth"].Store)
+	assert.True(t, inits["/auth"].Global)
+
+	// global не наследуется
+	assert.Equal(t, map[string]interface{}{"host": "localhost", "login": "roman"}, inits["/auth/deep"].Store)
+	assert.False(t, inits["/auth/deep"].Global)
+}
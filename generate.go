@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/MashinaMashina/api-tests/generator"
+	"github.com/MashinaMashina/api-tests/service"
+)
+
+// generate - подкоманда создания заготовок тестов по спецификации OpenAPI
+func generate(args []string) int {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	noColor := flags.Bool("nocolor", false, "disable output coloring")
+	spec := flags.String("spec", "", "OpenAPI 3 spec (yaml or json)")
+	dir := flags.String("dir", "tests", "directory to write tests to")
+	force := flags.Bool("force", false, "overwrite existing files")
+	_ = flags.Parse(args)
+
+	setupLogger(*noColor)
+
+	if *spec == "" {
+		log.Error().Msg("spec is required: api-tests generate -spec openapi.yaml")
+		return service.ExitErrored
+	}
+
+	files, err := generator.Generate(generator.Options{
+		Spec:  *spec,
+		Dir:   *dir,
+		Force: *force,
+	})
+
+	for _, file := range files {
+		log.Info().Str("file", file).Msg("created")
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msg("generating tests")
+		return service.ExitErrored
+	}
+
+	log.Info().Int("files", len(files)).Msg("tests generated")
+
+	return service.ExitPassed
+}
@@ -0,0 +1,580 @@
+// Package generator создает заготовки тестов по спецификации OpenAPI 3.
+//
+// Для каждой операции создается папка <tag>/<operation> с файлом теста,
+// в корне - init.yml с переменными host и token и значениями параметров пути.
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MashinaMashina/api-tests/test"
+	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/openapi"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// maxDepth - глубина вложенных правил fields, глубже поля не описываются
+const maxDepth = 3
+
+// Options - параметры генерации
+type Options struct {
+	// Spec - путь к спецификации
+	Spec string
+	// Dir - папка, в которую пишутся тесты
+	Dir string
+	// Force - перезаписывать существующие файлы
+	Force bool
+}
+
+// Generate создает тесты по спецификации и возвращает пути созданных файлов.
+// Существующие файлы без Force не перезаписываются.
+func Generate(options Options) ([]string, error) {
+	spec, err := openapi.Load(options.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		spec:  spec,
+		store: make(map[string]interface{}),
+	}
+
+	files := make(map[string]interface{})
+	for _, op := range spec.Operations() {
+		testcase := g.testcase(op)
+		files[filepath.Join(options.Dir, g.folder(op), "1-"+slug(operationName(op))+".yml")] = testcase
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no operations in spec '%s'", options.Spec)
+	}
+
+	files[filepath.Join(options.Dir, "init.yml")] = g.init(options)
+
+	return Write(files, options.Force)
+}
+
+// Write записывает описания тестов в yaml файлы и возвращает пути созданных файлов.
+// Ключ files - путь к файлу, значение - test.Case или test.Init.
+// Существующие файлы без force не перезаписываются.
+func Write(files map[string]interface{}, force bool) ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var created []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil && !force {
+			continue
+		}
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(files[path]); err != nil {
+			return created, fmt.Errorf("encoding '%s': %w", path, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return created, err
+		}
+
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return created, fmt.Errorf("writing '%s': %w", path, err)
+		}
+
+		created = append(created, path)
+	}
+
+	return created, nil
+}
+
+type generator struct {
+	spec *openapi.Spec
+	// store - переменные для init.yml: параметры пути и запроса
+	store map[string]interface{}
+	// secured - в спецификации есть операции с авторизацией
+	secured bool
+}
+
+// init возвращает init.yml с переменными
+func (g *generator) init(options Options) test.Init {
+	g.store["host"] = "localhost"
+	if servers := g.spec.Servers(); len(servers) > 0 {
+		if u, err := url.Parse(servers[0]); err == nil && u.Host != "" {
+			g.store["host"] = u.Host + strings.TrimSuffix(u.Path, "/")
+		}
+	}
+
+	if g.secured {
+		g.store["token"] = ""
+	}
+
+	init := test.Init{Store: g.store}
+
+	// Созданные тесты сразу проверяют контракт
+	if abs, err := filepath.Abs(options.Spec); err == nil {
+		if dir, err := filepath.Abs(options.Dir); err == nil {
+			if rel, err := filepath.Rel(dir, abs); err == nil {
+				init.OpenAPI = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	return init
+}
+
+// folder возвращает папку теста: <tag>/<operation>
+func (g *generator) folder(op openapi.Operation) string {
+	tag := "default"
+	if tags, ok := op.Spec["tags"].([]interface{}); ok && len(tags) > 0 {
+		tag = fmt.Sprint(tags[0])
+	}
+
+	return filepath.Join(slug(tag), slug(operationName(op)))
+}
+
+func operationName(op openapi.Operation) string {
+	if id, ok := op.Spec["operationId"].(string); ok && id != "" {
+		return id
+	}
+
+	return strings.ToLower(op.Method) + " " + op.Path
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^/{}]+)\}`)
+
+// testcase создает тест операции
+func (g *generator) testcase(op openapi.Operation) test.Case {
+	name := operationName(op)
+	if summary, ok := op.Spec["summary"].(string); ok && summary != "" {
+		name = summary
+	}
+
+	// Параметры пути и обязательные параметры запроса берутся из хранилища
+	path := pathParamRe.ReplaceAllStringFunc(op.Path, func(param string) string {
+		return "{{." + variable(param[1:len(param)-1]) + "}}"
+	})
+
+	var query []string
+	params, _ := op.Spec["parameters"].([]interface{})
+	for _, p := range params {
+		param, ok := g.spec.Resolve(p).(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		paramName, _ := param["name"].(string)
+		required, _ := param["required"].(bool)
+		v := variable(paramName)
+
+		switch param["in"] {
+		case "path":
+			g.store[v] = plain(g.example(param["example"], param["schema"], 0))
+		case "query":
+			if required {
+				g.store[v] = plain(g.example(param["example"], param["schema"], 0))
+				query = append(query, url.QueryEscape(paramName)+"={{urlquery ."+v+"}}")
+			}
+		}
+	}
+
+	requestURL := "https://{{.host}}" + path
+	if len(query) > 0 {
+		requestURL += "?" + strings.Join(query, "&")
+	}
+
+	testcase := test.Case{
+		Name: name,
+		Request: test.Request{
+			Method: op.Method,
+			URL:    requestURL,
+		},
+	}
+
+	headers := make(map[string]string)
+	if g.requiresAuth(op) {
+		g.secured = true
+		headers["Authorization"] = "Bearer {{.token}}"
+	}
+
+	if body, ok := g.spec.Resolve(op.Spec["requestBody"]).(map[string]interface{}); ok {
+		if media, ok := jsonMedia(body); ok {
+			b, err := json.MarshalIndent(g.example(mediaExample(media), media["schema"], 0), "", "  ")
+			if err == nil {
+				headers["Content-Type"] = "application/json"
+				testcase.Request.Body = string(b) + "\n"
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		testcase.Request.Headers = headers
+	}
+
+	code, response := g.successResponse(op)
+	if code != "" {
+		testcase.Response.Code = []rules.Rule{{Equal: &code}}
+	}
+
+	if media, ok := jsonMedia(response); ok {
+		if bodyRules := g.rules(media["schema"], 0); len(bodyRules) > 0 {
+			testcase.Response.Body = []validators.ValidatorDescr{{
+				Type:  "json",
+				Rules: bodyRules,
+			}}
+		}
+	}
+
+	return testcase
+}
+
+// requiresAuth проверяет, нужна ли операции авторизация
+func (g *generator) requiresAuth(op openapi.Operation) bool {
+	security, ok := op.Spec["security"].([]interface{})
+	if !ok {
+		security, _ = g.spec.Document()["security"].([]interface{})
+	}
+
+	for _, requirement := range security {
+		if req, ok := requirement.(map[string]interface{}); ok && len(req) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// successResponse возвращает первый успешный ответ операции
+func (g *generator) successResponse(op openapi.Operation) (string, map[string]interface{}) {
+	responses, _ := op.Spec["responses"].(map[string]interface{})
+
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if strings.ContainsAny(code, "Xx") {
+			continue
+		}
+
+		response, _ := g.spec.Resolve(responses[code]).(map[string]interface{})
+		return code, response
+	}
+
+	return "", nil
+}
+
+// rules создает правила валидатора json по схеме объекта
+func (g *generator) rules(schema interface{}, depth int) []rules.Rule {
+	sch := g.schema(schema)
+	properties, _ := sch["properties"].(map[string]interface{})
+	if len(properties) == 0 || depth >= maxDepth {
+		return nil
+	}
+
+	required := make(map[string]bool)
+	list, _ := sch["required"].([]interface{})
+	for _, name := range list {
+		required[fmt.Sprint(name)] = true
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []rules.Rule
+	for _, name := range names {
+		rule := g.rule(properties[name], depth)
+		rule.Key = name
+
+		if !required[name] {
+			notRequired := false
+			rule.Required = &notRequired
+		}
+
+		result = append(result, rule)
+	}
+
+	return result
+}
+
+// rule создает правило по схеме значения
+func (g *generator) rule(schema interface{}, depth int) rules.Rule {
+	sch := g.schema(schema)
+
+	var rule rules.Rule
+	switch schemaType(sch) {
+	case "integer":
+		rule.Type = rules.TypeInteger
+	case "number":
+		rule.Type = rules.TypeFloat
+	case "boolean":
+		rule.Type = rules.TypeBoolean
+	case "object":
+		rule.Type = rules.TypeObject
+		rule.Fields = g.rules(sch, depth+1)
+	case "array":
+		rule.Type = rules.TypeArray
+		items := g.schema(sch["items"])
+		if schemaType(items) == "object" {
+			if fields := g.rules(items, depth+1); len(fields) > 0 {
+				rule.Fields = []rules.Rule{{Type: rules.TypeObject, Fields: fields}}
+			}
+		}
+	default:
+		rule.Type = formatType(sch["format"])
+	}
+
+	return rule
+}
+
+// formatType возвращает тип правила для формата строки из схемы
+func formatType(format interface{}) rules.RuleType {
+	switch format {
+	case "uuid":
+		return rules.TypeUUID
+	case "email":
+		return rules.TypeEmail
+	case "uri", "url":
+		return rules.TypeURL
+	case "date-time":
+		return rules.TypeDatetime
+	case "ipv4", "ipv6":
+		return rules.TypeIP
+	case "byte":
+		return rules.TypeBase64
+	}
+
+	return rules.TypeString
+}
+
+// schema разрешает $ref и объединяет части allOf
+func (g *generator) schema(schema interface{}) map[string]interface{} {
+	sch, _ := g.spec.Resolve(schema).(map[string]interface{})
+	all, ok := sch["allOf"].([]interface{})
+	if !ok {
+		return sch
+	}
+
+	merged := make(map[string]interface{})
+	properties := make(map[string]interface{})
+	var required []interface{}
+
+	for _, part := range append([]interface{}{sch}, all...) {
+		p := g.schema(part)
+		for k, v := range p {
+			merged[k] = v
+		}
+		if props, ok := p["properties"].(map[string]interface{}); ok {
+			for k, v := range props {
+				properties[k] = v
+			}
+		}
+		if req, ok := p["required"].([]interface{}); ok {
+			required = append(required, req...)
+		}
+	}
+
+	delete(merged, "allOf")
+	merged["properties"] = properties
+	merged["required"] = required
+	if _, ok := merged["type"]; !ok {
+		merged["type"] = "object"
+	}
+
+	return merged
+}
+
+// example возвращает пример значения: явный пример, пример из схемы или значение по типу
+func (g *generator) example(example, schema interface{}, depth int) interface{} {
+	if example != nil {
+		return example
+	}
+
+	sch := g.schema(schema)
+	for _, key := range []string{"example", "default"} {
+		if v, ok := sch[key]; ok {
+			return v
+		}
+	}
+	if enum, ok := sch["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch schemaType(sch) {
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "object":
+		obj := make(map[string]interface{})
+		if depth < maxDepth {
+			properties, _ := sch["properties"].(map[string]interface{})
+			for name, prop := range properties {
+				obj[name] = g.example(nil, prop, depth+1)
+			}
+		}
+		return obj
+	case "array":
+		if depth >= maxDepth {
+			return []interface{}{}
+		}
+		return []interface{}{g.example(nil, sch["items"], depth+1)}
+	}
+
+	switch sch["format"] {
+	case "email":
+		return "user-{{randString 8}}@example.com"
+	case "uuid":
+		return "{{uuid}}"
+	case "date-time":
+		return "{{now | date `rfc3339`}}"
+	case "date":
+		return "{{now | date `date`}}"
+	}
+
+	return "string"
+}
+
+// plain заменяет json.Number на обычные числа, чтобы в yaml они не попали строками
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = plain(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = plain(item)
+		}
+	}
+
+	return value
+}
+
+func schemaType(sch map[string]interface{}) string {
+	switch t := sch["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+
+	if _, ok := sch["properties"]; ok {
+		return "object"
+	}
+
+	return ""
+}
+
+// jsonMedia возвращает описание JSON тела запроса или ответа
+func jsonMedia(described map[string]interface{}) (map[string]interface{}, bool) {
+	content, _ := described["content"].(map[string]interface{})
+
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			media, ok := content[t].(map[string]interface{})
+			return media, ok
+		}
+	}
+
+	return nil, false
+}
+
+// mediaExample возвращает пример тела из example или первого из examples
+func mediaExample(media map[string]interface{}) interface{} {
+	if example, ok := media["example"]; ok {
+		return example
+	}
+
+	examples, _ := media["examples"].(map[string]interface{})
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if example, ok := examples[name].(map[string]interface{}); ok {
+			if value, ok := example["value"]; ok {
+				return value
+			}
+		}
+	}
+
+	return nil
+}
+
+// slug приводит имя к виду, пригодному для имени файла
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// variable приводит имя параметра к имени переменной шаблона
+func variable(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/finder"
+)
+
+const spec = `
+openapi: 3.0.3
+servers:
+  - url: https://example-api.com/api
+security:
+  - bearer: []
+paths:
+  /users/{userId}:
+    get:
+      operationId: getUser
+      summary: Получение пользователя
+      tags: [users]
+      parameters:
+        - name: userId
+          in: path
+          required: true
+          schema:
+            type: integer
+            example: 5
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /users:
+    post:
+      operationId: createUser
+      tags: [users]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        201:
+          description: created
+  /health:
+    get:
+      security: []
+      responses:
+        204:
+          description: ok
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+        email:
+          type: string
+          format: email
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "openapi.yml")
+	assert.Nil(t, os.WriteFile(specPath, []byte(spec), 0o644))
+
+	testsDir := filepath.Join(dir, "tests")
+	files, err := Generate(Options{Spec: specPath, Dir: testsDir})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join(testsDir, "default/get-health/1-get-health.yml"),
+		filepath.Join(testsDir, "init.yml"),
+		filepath.Join(testsDir, "users/createuser/1-createuser.yml"),
+		filepath.Join(testsDir, "users/getuser/1-getuser.yml"),
+	}, files)
+
+	b, err := os.ReadFile(filepath.Join(testsDir, "users/getuser/1-getuser.yml"))
+	assert.Nil(t, err)
+	assert.Equal(t, `name: Получение пользователя
+request:
+  url: https://{{.host}}/users/{{.userId}}
+  method: GET
+  headers:
+    Authorization: Bearer {{.token}}
+response:
+  code:
+    - equal: "200"
+  body:
+    - type: json
+      rules:
+        - type: email
+          key: email
+          required: false
+        - type: integer
+          key: id
+`, string(b))
+
+	b, err = os.ReadFile(filepath.Join(testsDir, "init.yml"))
+	assert.Nil(t, err)
+	assert.Equal(t, "store:\n  host: example-api.com/api\n  token: \"\"\n  userId: 5\nopenapi: ../openapi.yml\n", string(b))
+
+	// Созданные тесты читаются так же, как написанные вручную
+	groups, errs := finder.Find(testsDir, "")
+	assert.Empty(t, errs)
+	assert.Len(t, groups, 3)
+
+	// Существующие файлы не перезаписываются
+	files, err = Generate(Options{Spec: specPath, Dir: testsDir})
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+}
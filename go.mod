@@ -3,11 +3,14 @@ module github.com/MashinaMashina/api-tests
 go 1.18
 
 require (
+	github.com/andybalholm/cascadia v1.3.2
+	github.com/antchfx/xpath v1.3.5
 	github.com/buger/jsonparser v1.1.1
 	github.com/golang-jwt/jwt/v4 v4.4.2
 	github.com/gorilla/websocket v1.5.0
 	github.com/rs/zerolog v1.28.0
 	github.com/stretchr/testify v1.8.0
+	golang.org/x/net v0.11.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -18,6 +21,6 @@ require (
 	github.com/mattn/go-isatty v0.0.16 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.0.0-20220829200755-d48e67d00261 // indirect
+	golang.org/x/sys v0.9.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 )
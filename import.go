@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/MashinaMashina/api-tests/importer"
+	"github.com/MashinaMashina/api-tests/service"
+)
+
+// importRequests - подкоманда создания тестов из HAR файла или команд curl
+func importRequests(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	noColor := flags.Bool("nocolor", false, "disable output coloring")
+	har := flags.String("har", "", "HAR file exported from browser DevTools")
+	curl := flags.String("curl", "", "file with curl commands, one per line")
+	dir := flags.String("dir", "", "group directory to write tests to")
+	force := flags.Bool("force", false, "overwrite existing files")
+	_ = flags.Parse(args)
+
+	setupLogger(*noColor)
+
+	if (*har == "") == (*curl == "") || *dir == "" {
+		log.Error().Msg("usage: api-tests import (-har file.har | -curl commands.txt) -dir tests/group")
+		return service.ExitErrored
+	}
+
+	path := *har
+	if path == "" {
+		path = *curl
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Error().Err(err).Msg("reading requests")
+		return service.ExitErrored
+	}
+
+	var entries []importer.Entry
+	if *har != "" {
+		entries, err = importer.ParseHAR(b)
+	} else {
+		entries, err = importer.ParseCurl(string(b))
+	}
+
+	if err != nil {
+		log.Error().Err(err).Str("file", path).Msg("parsing requests")
+		return service.ExitErrored
+	}
+
+	files, err := importer.Write(entries, importer.Options{
+		Dir:   *dir,
+		Force: *force,
+	})
+
+	for _, file := range files {
+		log.Info().Str("file", file).Msg("created")
+	}
+
+	if err != nil {
+		log.Error().Err(err).Msg("importing requests")
+		return service.ExitErrored
+	}
+
+	log.Info().Int("files", len(files)).Msg("requests imported")
+
+	return service.ExitPassed
+}
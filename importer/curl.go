@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ParseCurl разбирает список команд curl, например, скопированных из DevTools через "Copy as cURL".
+// Команды разделяются переводом строки, перенос строки внутри команды - через \.
+func ParseCurl(text string) ([]Entry, error) {
+	// Склеиваем перенесенные строки
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\\\n", " ")
+
+	var entries []Entry
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		args, err := splitArgs(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		entry, err := parseCurlArgs(args)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func parseCurlArgs(args []string) (Entry, error) {
+	if len(args) == 0 || args[0] != "curl" {
+		return Entry{}, fmt.Errorf("command must start with curl")
+	}
+
+	var entry Entry
+	var data []string
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		next := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch arg {
+		case "-X", "--request":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			entry.Method = strings.ToUpper(v)
+		case "-H", "--header":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			parts := strings.SplitN(v, ":", 2)
+			if len(parts) == 2 && keepHeader(parts[0]) {
+				entry.Headers = append(entry.Headers, Header{Name: strings.TrimSpace(parts[0]), Value: strings.TrimSpace(parts[1])})
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii", "--data-urlencode":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			data = append(data, v)
+		case "-u", "--user":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			entry.Headers = append(entry.Headers, Header{Name: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(v))})
+		case "-b", "--cookie":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			entry.Headers = append(entry.Headers, Header{Name: "Cookie", Value: v})
+		case "--url":
+			v, err := next()
+			if err != nil {
+				return entry, err
+			}
+			entry.URL = v
+		case "-A", "--user-agent", "-e", "--referer", "-o", "--output", "-m", "--max-time", "--connect-timeout":
+			// Параметры со значением, которые не влияют на тест
+			if _, err := next(); err != nil {
+				return entry, err
+			}
+		default:
+			if !strings.HasPrefix(arg, "-") && entry.URL == "" {
+				entry.URL = arg
+			}
+		}
+	}
+
+	if entry.URL == "" {
+		return entry, fmt.Errorf("url not found")
+	}
+
+	entry.Body = strings.Join(data, "&")
+	if entry.Method == "" {
+		entry.Method = "GET"
+		if len(data) > 0 {
+			entry.Method = "POST"
+		}
+	}
+
+	return entry, nil
+}
+
+// splitArgs разбивает команду на аргументы с учетом кавычек, как это делает shell.
+// Поддерживается и запись $'...' из "Copy as cURL" в Chrome.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+	)
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		case c == '\'':
+			inArg = true
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			current.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+		case c == '$' && i+1 < len(line) && line[i+1] == '\'':
+			inArg = true
+			i += 2
+			for ; i < len(line) && line[i] != '\''; i++ {
+				if line[i] == '\\' && i+1 < len(line) {
+					i++
+					current.WriteByte(unescape(line[i]))
+					continue
+				}
+				current.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+		case c == '"':
+			inArg = true
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) && strings.IndexByte("\\\"$`", line[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+		case c == '\\' && i+1 < len(line):
+			inArg = true
+			i++
+			current.WriteByte(line[i])
+		default:
+			inArg = true
+			current.WriteByte(c)
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	}
+
+	return c
+}
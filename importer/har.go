@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	ResourceType string `json:"_resourceType"`
+	Request      struct {
+		Method   string      `json:"method"`
+		URL      string      `json:"url"`
+		Headers  []harHeader `json:"headers"`
+		PostData *struct {
+			Text string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ParseHAR разбирает HAR файл, экспортированный из DevTools браузера.
+// Загрузка страниц, скриптов, картинок и других ресурсов пропускается, остаются только xhr и fetch запросы.
+func ParseHAR(b []byte) ([]Entry, error) {
+	var file harFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, fmt.Errorf("decoding har: %w", err)
+	}
+
+	var entries []Entry
+	for _, item := range file.Log.Entries {
+		if item.ResourceType != "" && item.ResourceType != "xhr" && item.ResourceType != "fetch" {
+			continue
+		}
+
+		entry := Entry{
+			Method: strings.ToUpper(item.Request.Method),
+			URL:    item.Request.URL,
+			Status: item.Response.Status,
+		}
+
+		for _, h := range item.Request.Headers {
+			if keepHeader(h.Name) {
+				entry.Headers = append(entry.Headers, Header{Name: h.Name, Value: h.Value})
+			}
+		}
+
+		if item.Request.PostData != nil {
+			entry.Body = item.Request.PostData.Text
+		}
+
+		entry.ResponseBody = item.Response.Content.Text
+		if item.Response.Content.Encoding == "base64" {
+			if decoded, err := base64.StdEncoding.DecodeString(entry.ResponseBody); err == nil {
+				entry.ResponseBody = string(decoded)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// keepHeader отсекает заголовки, которые браузер и curl добавляют сами
+func keepHeader(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, ":") || strings.HasPrefix(name, "sec-") {
+		return false
+	}
+
+	switch name {
+	case "host", "content-length", "connection", "accept-encoding", "accept-language", "user-agent",
+		"referer", "origin", "pragma", "cache-control", "dnt", "priority", "te":
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,381 @@
+// Package importer создает тесты из записанных запросов: HAR файла браузера или списка команд curl.
+//
+// Каждый запрос становится отдельным тестом группы. Значения из ответов (токены, id),
+// которые встречаются в следующих запросах, сохраняются в переменные правилом store
+// и подставляются в следующие запросы как {{.имя}}.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/MashinaMashina/api-tests/generator"
+	"github.com/MashinaMashina/api-tests/test"
+	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// Entry - записанный запрос и, если известен, ответ на него
+type Entry struct {
+	Method  string
+	URL     string
+	Headers []Header
+	Body    string
+	// Status - код ответа, 0 - ответ неизвестен (например, для curl)
+	Status       int
+	ResponseBody string
+}
+
+// Header - заголовок запроса. Порядок заголовков сохраняется как в записи.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Options - параметры импорта
+type Options struct {
+	// Dir - папка группы, в которую пишутся тесты
+	Dir string
+	// Force - перезаписывать существующие файлы
+	Force bool
+}
+
+// Write создает тесты группы из записанных запросов и возвращает пути созданных файлов
+func Write(entries []Entry, options Options) ([]string, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no requests to import")
+	}
+
+	init, cases := Convert(entries)
+
+	// Имена с ведущими нулями, чтобы сортировка по имени совпадала с порядком запросов
+	width := len(strconv.Itoa(len(cases)))
+
+	files := make(map[string]interface{}, len(cases)+1)
+	for i, testcase := range cases {
+		name := fmt.Sprintf("%0*d-%s.yml", width, i+1, slug(entries[i]))
+		files[filepath.Join(options.Dir, name)] = testcase
+	}
+
+	if len(init.Store) > 0 {
+		files[filepath.Join(options.Dir, "init.yml")] = init
+	}
+
+	return generator.Write(files, options.Force)
+}
+
+// Convert превращает записанные запросы в тесты.
+// Адрес сервера первого запроса выносится в переменную host файла init.
+func Convert(entries []Entry) (test.Init, []test.Case) {
+	init := test.Init{Store: make(map[string]interface{})}
+
+	var host string
+	if u, err := url.Parse(entries[0].URL); err == nil && u.Host != "" {
+		host = u.Scheme + "://" + u.Host
+		init.Store["host"] = u.Host
+	}
+
+	cases := make([]test.Case, len(entries))
+	for i, entry := range entries {
+		cases[i] = test.Case{
+			Name: entry.Method + " " + pathOf(entry.URL),
+			Request: test.Request{
+				Method: entry.Method,
+				URL:    entry.URL,
+				Body:   entry.Body,
+			},
+		}
+
+		if host != "" && strings.HasPrefix(entry.URL, host) {
+			scheme := strings.SplitN(host, "://", 2)[0]
+			cases[i].Request.URL = scheme + "://{{.host}}" + strings.TrimPrefix(entry.URL, host)
+		}
+
+		if len(entry.Headers) > 0 {
+			cases[i].Request.Headers = make(map[string]string, len(entry.Headers))
+			for _, h := range entry.Headers {
+				cases[i].Request.Headers[h.Name] = h.Value
+			}
+		}
+
+		if entry.Status != 0 {
+			code := strconv.Itoa(entry.Status)
+			cases[i].Response.Code = []rules.Rule{{Equal: &code}}
+		}
+	}
+
+	link(entries, cases)
+
+	return init, cases
+}
+
+// value - значение из ответа, которое можно передать в следующие запросы
+type value struct {
+	text string
+	key  string
+	typo rules.RuleType
+}
+
+// link находит значения из ответов, которые используются в следующих запросах,
+// добавляет правила store и заменяет значения переменными
+func link(entries []Entry, cases []test.Case) {
+	names := make(map[string]int)
+	// sent - значения, которые уже отправлялись в запросах, они не получены от сервера
+	var sent []string
+
+	for i, entry := range entries {
+		sent = append(sent, entry.URL, entry.Body)
+		for _, h := range entry.Headers {
+			sent = append(sent, h.Value)
+		}
+
+		for _, v := range responseValues(entry.ResponseBody) {
+			if containsValue(sent, v.text) || !usedLater(cases[i+1:], v.text) {
+				continue
+			}
+
+			name := variableName(v.key, names)
+			storeName := name
+			rule := rules.Rule{Key: v.key, Type: v.typo, Store: &storeName}
+			addBodyRule(&cases[i], rule)
+
+			for j := i + 1; j < len(cases); j++ {
+				replaceInCase(&cases[j], v.text, "{{."+name+"}}")
+			}
+		}
+	}
+}
+
+// responseValues возвращает значения JSON ответа, которые похожи на идентификаторы:
+// строки от 4 символов и числа от 3 цифр. Длинные значения идут первыми,
+// чтобы при замене не задеть их части.
+func responseValues(body string) []value {
+	data, err := jsonschema.Decode([]byte(body))
+	if err != nil {
+		return nil
+	}
+
+	var values []value
+	collect(data, "", &values)
+
+	sort.SliceStable(values, func(i, j int) bool {
+		return len(values[i].text) > len(values[j].text)
+	})
+
+	return values
+}
+
+func collect(data interface{}, key string, values *[]value) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			child := k
+			if !isIdent(k) {
+				child = "['" + k + "']"
+				if key == "" {
+					child = "$" + child
+				}
+			} else if key != "" {
+				child = "." + k
+			}
+			collect(v[k], key+child, values)
+		}
+	case []interface{}:
+		for i, item := range v {
+			prefix := key
+			if prefix == "" {
+				prefix = "$"
+			}
+			collect(item, prefix+"["+strconv.Itoa(i)+"]", values)
+		}
+	case string:
+		if len(v) >= 4 && key != "" {
+			*values = append(*values, value{text: v, key: key, typo: rules.TypeString})
+		}
+	case json.Number:
+		text := v.String()
+		if _, err := strconv.ParseInt(text, 10, 64); err == nil && len(strings.TrimPrefix(text, "-")) >= 3 && key != "" {
+			*values = append(*values, value{text: text, key: key, typo: rules.TypeInteger})
+		}
+	}
+}
+
+func addBodyRule(testcase *test.Case, rule rules.Rule) {
+	body := testcase.Response.Body
+	if len(body) == 0 {
+		body = []validators.ValidatorDescr{{Type: "json"}}
+	}
+
+	body[0].Rules = append(body[0].Rules, rule)
+	testcase.Response.Body = body
+}
+
+func usedLater(cases []test.Case, text string) bool {
+	for _, testcase := range cases {
+		if caseContains(testcase, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func caseContains(testcase test.Case, text string) bool {
+	parts := []string{testcase.Request.URL, testcase.Request.Body}
+	for k, v := range testcase.Request.Headers {
+		parts = append(parts, k, v)
+	}
+
+	return containsValue(parts, text)
+}
+
+func containsValue(parts []string, text string) bool {
+	for _, part := range parts {
+		if indexValue(part, text, 0) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// indexValue ищет значение целиком: вокруг него не должно быть букв и цифр,
+// чтобы id 123 не нашелся внутри 51234
+func indexValue(s, text string, from int) int {
+	for from <= len(s)-len(text) {
+		i := strings.Index(s[from:], text)
+		if i < 0 {
+			return -1
+		}
+		i += from
+
+		end := i + len(text)
+		if (i == 0 || !isWordByte(s[i-1])) && (end == len(s) || !isWordByte(s[end])) {
+			return i
+		}
+
+		from = i + 1
+	}
+
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+func replaceValue(s, text, replacement string) string {
+	var b strings.Builder
+	from := 0
+
+	for {
+		i := indexValue(s, text, from)
+		if i < 0 {
+			b.WriteString(s[from:])
+			return b.String()
+		}
+
+		b.WriteString(s[from:i])
+		b.WriteString(replacement)
+		from = i + len(text)
+	}
+}
+
+func replaceInCase(testcase *test.Case, text, replacement string) {
+	testcase.Request.URL = replaceValue(testcase.Request.URL, text, replacement)
+	testcase.Request.Body = replaceValue(testcase.Request.Body, text, replacement)
+
+	if len(testcase.Request.Headers) > 0 {
+		headers := make(map[string]string, len(testcase.Request.Headers))
+		for k, v := range testcase.Request.Headers {
+			headers[k] = replaceValue(v, text, replacement)
+		}
+		testcase.Request.Headers = headers
+	}
+}
+
+// variableName возвращает уникальное имя переменной по последней части ключа
+func variableName(key string, names map[string]int) string {
+	name := key
+	if i := strings.LastIndexAny(name, ".["); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Trim(name, "'[]$")
+
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+
+	name = b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "value" + name
+	}
+
+	names[name]++
+	if names[name] > 1 {
+		name += strconv.Itoa(names[name])
+	}
+
+	return name
+}
+
+func isIdent(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, r := range key {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-'))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func pathOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" {
+		return rawURL
+	}
+
+	return u.Path
+}
+
+// slug возвращает часть имени файла: метод и последний сегмент пути
+func slug(entry Entry) string {
+	segments := strings.Split(strings.Trim(pathOf(entry.URL), "/"), "/")
+	last := segments[len(segments)-1]
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(entry.Method))
+
+	if last != "" {
+		b.WriteByte('-')
+		for _, r := range strings.ToLower(last) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte('-')
+			}
+		}
+	}
+
+	return strings.Trim(b.String(), "-")
+}
@@ -0,0 +1,118 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/finder"
+)
+
+const har = `{"log": {"entries": [
+  {
+    "_resourceType": "fetch",
+    "request": {
+      "method": "POST",
+      "url": "https://example-api.com/api/auth/login",
+      "headers": [{"name": "Content-Type", "value": "application/json"}, {"name": "User-Agent", "value": "Chrome"}],
+      "postData": {"text": "{\"username\":\"roman\",\"password\":\"qwerty123\"}"}
+    },
+    "response": {"status": 200, "content": {"text": "{\"success\":true,\"data\":{\"token\":\"eyJhbGciOi.payload.sign\",\"user\":{\"id\":5123,\"name\":\"roman\"}}}"}}
+  },
+  {
+    "_resourceType": "image",
+    "request": {"method": "GET", "url": "https://example-api.com/logo.png", "headers": []},
+    "response": {"status": 200, "content": {"text": ""}}
+  },
+  {
+    "_resourceType": "xhr",
+    "request": {
+      "method": "GET",
+      "url": "https://example-api.com/api/users/5123?expand=roman",
+      "headers": [{"name": "Authorization", "value": "Bearer eyJhbGciOi.payload.sign"}]
+    },
+    "response": {"status": 200, "content": {"text": "eyJpZCI6NTEyM30=", "encoding": "base64"}}
+  }
+]}}`
+
+func TestImportHAR(t *testing.T) {
+	entries, err := ParseHAR([]byte(har))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Len(t, entries, 2)
+	assert.Equal(t, `{"id":5123}`, entries[1].ResponseBody)
+
+	dir := t.TempDir()
+	files, err := Write(entries, Options{Dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "1-post-login.yml"),
+		filepath.Join(dir, "2-get-5123.yml"),
+		filepath.Join(dir, "init.yml"),
+	}, files)
+
+	b, err := os.ReadFile(files[0])
+	assert.Nil(t, err)
+	assert.Equal(t, `name: POST /api/auth/login
+request:
+  url: https://{{.host}}/api/auth/login
+  method: POST
+  body: '{"username":"roman","password":"qwerty123"}'
+  headers:
+    Content-Type: application/json
+response:
+  code:
+    - equal: "200"
+  body:
+    - type: json
+      rules:
+        - type: string
+          key: data.token
+          store: token
+        - type: integer
+          key: data.user.id
+          store: id
+`, string(b))
+
+	b, err = os.ReadFile(files[1])
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), "url: https://{{.host}}/api/users/{{.id}}?expand=roman\n")
+	assert.Contains(t, string(b), "Authorization: Bearer {{.token}}\n")
+
+	groups, errs := finder.Find(dir, "")
+	assert.Empty(t, errs)
+	if assert.Len(t, groups, 1) {
+		assert.Equal(t, "example-api.com", groups[0].Init.Store["host"])
+	}
+}
+
+func TestParseCurl(t *testing.T) {
+	entries, err := ParseCurl(`curl 'https://example-api.com/api/auth/login' \
+  -H 'Content-Type: application/json' \
+  -H 'sec-ch-ua: "Chromium"' \
+  --data-raw $'{"username":"roman","note":"it\'s"}' --compressed
+
+# комментарий
+curl -X DELETE "https://example-api.com/api/users/5" -u admin:secret
+`)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, []Entry{
+		{
+			Method:  "POST",
+			URL:     "https://example-api.com/api/auth/login",
+			Headers: []Header{{Name: "Content-Type", Value: "application/json"}},
+			Body:    `{"username":"roman","note":"it's"}`,
+		},
+		{
+			Method:  "DELETE",
+			URL:     "https://example-api.com/api/users/5",
+			Headers: []Header{{Name: "Authorization", Value: "Basic YWRtaW46c2VjcmV0"}},
+		},
+	}, entries)
+}
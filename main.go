@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"io"
 	"os"
 	"strings"
 
@@ -9,24 +10,34 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
 
+	"github.com/MashinaMashina/api-tests/report"
 	"github.com/MashinaMashina/api-tests/service"
 )
 
 func main() {
+	// Подкоманды
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate":
+			os.Exit(generate(os.Args[2:]))
+		case "import":
+			os.Exit(importRequests(os.Args[2:]))
+		}
+	}
+
 	noColor := flag.Bool("nocolor", false, "disable output coloring")
 	loglevel := flag.String("level", "trace", "log level (panic, fatal, error, warn, info, debug, trace)")
 	dir := flag.String("dir", "tests", "tests directory")
 	pattern := flag.String("pattern", "", "pattern for tests")
+	continueOnFailure := flag.Bool("continue", false, "continue running group tests after a failed test")
+	parallel := flag.Int("parallel", 1, "number of test groups running at the same time")
+	openAPI := flag.String("openapi", "", "OpenAPI 3 spec to validate all HTTP requests and responses against (schemas support a subset of JSON Schema)")
+	updateSnapshots := flag.Bool("update-snapshots", false, "rewrite response snapshots instead of comparing with them")
+	var reports report.Targets
+	flag.Var(&reports, "report", "write report in format=path form, can be repeated (formats: junit, json, html)")
 	flag.Parse()
 
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
-	zerolog.TimestampFieldName = "_t"
-	zerolog.LevelFieldName = "_l"
-	zerolog.MessageFieldName = "_m"
-	zerolog.ErrorFieldName = "_e"
-	w := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "15:04:05", NoColor: *noColor}
-	log.Logger = zerolog.New(w).With().Timestamp().Logger()
+	newLogger := setupLogger(*noColor)
 
 	level, err := zerolog.ParseLevel(strings.ToLower(*loglevel))
 	if err != nil {
@@ -35,5 +46,42 @@ func main() {
 	}
 	zerolog.SetGlobalLevel(level)
 
-	service.Run(*dir, *pattern)
+	result := service.Run(service.Config{
+		Dir:               *dir,
+		Pattern:           *pattern,
+		ContinueOnFailure: *continueOnFailure,
+		Parallel:          *parallel,
+		OpenAPI:           *openAPI,
+		UpdateSnapshots:   *updateSnapshots,
+		NewLogger:         newLogger,
+	})
+
+	err = report.Write(reports, report.Results{
+		Groups:   result.Groups,
+		Errors:   result.Errors,
+		Duration: result.Duration,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to write report")
+		os.Exit(service.ExitErrored)
+	}
+
+	os.Exit(result.ExitCode())
+}
+
+// setupLogger настраивает формат лога и возвращает фабрику логгеров
+func setupLogger(noColor bool) func(out io.Writer) zerolog.Logger {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
+	zerolog.TimestampFieldName = "_t"
+	zerolog.LevelFieldName = "_l"
+	zerolog.MessageFieldName = "_m"
+	zerolog.ErrorFieldName = "_e"
+	newLogger := func(out io.Writer) zerolog.Logger {
+		w := zerolog.ConsoleWriter{Out: out, TimeFormat: "15:04:05", NoColor: noColor}
+		return zerolog.New(w).With().Timestamp().Logger()
+	}
+	log.Logger = newLogger(os.Stdout)
+
+	return newLogger
 }
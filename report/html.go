@@ -0,0 +1,103 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// HTML - отчет одним html файлом без внешних зависимостей.
+// Запрос, ответ и проверенные правила каждого теста раскрываются по клику,
+// у не прошедших тестов они раскрыты сразу.
+type HTML struct{}
+
+type htmlData struct {
+	Summary jsonSummary
+	Groups  []test.GroupResult
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": func(d time.Duration) string {
+		return d.Round(time.Millisecond).String()
+	},
+	"count": func(group test.GroupResult, status string) int {
+		return group.Count(test.Status(status))
+	},
+	"details": details,
+}).Parse(htmlLayout))
+
+func (HTML) Write(w io.Writer, results Results) error {
+	groups := results.groups()
+
+	return htmlTemplate.Execute(w, htmlData{
+		Summary: summary(groups, results.Duration),
+		Groups:  groups,
+	})
+}
+
+const htmlLayout = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>api-tests report</title>
+<style>
+body { font-family: sans-serif; margin: 20px; color: #222; }
+h2 { margin-bottom: 4px; }
+.summary span { margin-right: 16px; }
+.passed { color: #2e7d32; }
+.failed, .errored { color: #c62828; }
+.skipped { color: #757575; }
+.warning { color: #ef6c00; }
+details { margin: 4px 0 4px 16px; }
+summary { cursor: pointer; }
+pre { background: #f5f5f5; padding: 8px; overflow-x: auto; white-space: pre-wrap; }
+table { border-collapse: collapse; margin: 8px 0; }
+td, th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>api-tests report</h1>
+<div class="summary">
+<span class="passed">passed: {{.Summary.Passed}}</span>
+<span class="failed">failed: {{.Summary.Failed}}</span>
+<span class="skipped">skipped: {{.Summary.Skipped}}</span>
+<span class="errored">errored: {{.Summary.Errored}}</span>
+<span>duration: {{.Summary.DurationMs}}ms</span>
+</div>
+{{range .Groups}}
+<h2>{{.Name}}</h2>
+<div class="summary">
+<span class="passed">passed: {{count . "passed"}}</span>
+<span class="failed">failed: {{count . "failed"}}</span>
+<span class="skipped">skipped: {{count . "skipped"}}</span>
+<span class="errored">errored: {{count . "errored"}}</span>
+<span>duration: {{duration .Duration}}</span>
+</div>
+{{range .Cases}}
+<details{{if or (eq .Status "failed") (eq .Status "errored")}} open{{end}}>
+<summary><span class="{{.Status}}">{{.Status}}</span>{{if .Warnings}} <span class="warning">warnings: {{len .Warnings}}</span>{{end}} {{.Name}} <small>{{.Filename}}, {{duration .Duration}}</small></summary>
+{{range .Errors}}<div class="failed">{{.}}</div>{{end}}
+{{range .Warnings}}<div class="warning">warning: {{.}}</div>{{end}}
+{{if .Rules}}
+<table>
+<tr><th>validator</th><th>key</th><th>expected</th><th>actual</th><th>result</th></tr>
+{{range .Rules}}
+<tr>
+<td>{{.Validator}}[{{.Index}}]</td>
+<td>{{.Key}}</td>
+<td>{{.Expected}}</td>
+<td>{{.Actual}}</td>
+<td>{{if .Passed}}<span class="passed">passed</span>{{else if eq .Severity "error"}}<span class="failed">{{.Message}}</span>{{else}}<span class="warning">{{.Severity}}: {{.Message}}</span>{{end}}</td>
+</tr>
+{{end}}
+</table>
+{{end}}
+{{with details .}}<pre>{{.}}</pre>{{end}}
+</details>
+{{end}}
+{{end}}
+</body>
+</html>
+`
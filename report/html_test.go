@@ -0,0 +1,161 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+func TestHTML(t *testing.T) {
+	cases := []struct {
+		Name     string
+		results  Results
+		contains []string
+		excludes []string
+	}{
+		{
+			Name: "Итоги запуска и группы",
+			results: Results{Duration: 1500 * time.Millisecond, Groups: []test.GroupResult{{
+				Name:     "/reports",
+				Duration: 1500 * time.Millisecond,
+				Cases: []test.CaseResult{
+					{Name: "Создание отчета", Status: test.StatusPassed},
+					{Name: "Получение отчета", Status: test.StatusFailed},
+					{Name: "Удаление отчета", Status: test.StatusSkipped},
+				},
+			}}},
+			contains: []string{
+				`<span class="passed">passed: 1</span>`,
+				`<span class="failed">failed: 1</span>`,
+				`<span class="skipped">skipped: 1</span>`,
+				`<span>duration: 1500ms</span>`,
+				`<h2>/reports</h2>`,
+				`<span>duration: 1.5s</span>`,
+			},
+		},
+		{
+			Name: "Упавший тест раскрыт сразу, прошедший - нет",
+			results: Results{Groups: []test.GroupResult{{
+				Name: "/reports",
+				Cases: []test.CaseResult{
+					{Name: "Создание отчета", Filename: "1-create.yml", Status: test.StatusPassed, Duration: time.Second},
+					{Name: "Получение отчета", Filename: "2-get.yml", Status: test.StatusFailed, Duration: 500 * time.Millisecond},
+				},
+			}}},
+			contains: []string{
+				"<details>\n<summary><span class=\"passed\">passed</span> Создание отчета <small>1-create.yml, 1s</small></summary>",
+				"<details open>\n<summary><span class=\"failed\">failed</span> Получение отчета <small>2-get.yml, 500ms</small></summary>",
+			},
+		},
+		{
+			Name: "Правила с ожидаемым и фактическим значением",
+			results: Results{Groups: []test.GroupResult{{
+				Name: "/reports",
+				Cases: []test.CaseResult{{
+					Name:   "Получение отчета",
+					Status: test.StatusFailed,
+					Errors: []string{"response validate: field 'status': must be '2'"},
+					Rules: []test.RuleResult{
+						{Validator: "code", Index: 0, Expected: "equal '200'", Passed: true},
+						{
+							Validator: "json",
+							Index:     1,
+							Key:       "status",
+							Expected:  "equal '2'",
+							Actual:    "1",
+							Message:   "response validate: field 'status': must be '2'",
+							Severity:  "error",
+						},
+					},
+				}},
+			}}},
+			contains: []string{
+				`<div class="failed">response validate: field &#39;status&#39;: must be &#39;2&#39;</div>`,
+				`<tr>
+<td>code[0]</td>
+<td></td>
+<td>equal &#39;200&#39;</td>
+<td></td>
+<td><span class="passed">passed</span></td>
+</tr>`,
+				`<tr>
+<td>json[1]</td>
+<td>status</td>
+<td>equal &#39;2&#39;</td>
+<td>1</td>
+<td><span class="failed">response validate: field &#39;status&#39;: must be &#39;2&#39;</span></td>
+</tr>`,
+			},
+		},
+		{
+			Name: "Предупреждения не роняют тест",
+			results: Results{Groups: []test.GroupResult{{
+				Name: "/reports",
+				Cases: []test.CaseResult{{
+					Name:     "Создание отчета",
+					Filename: "1-create.yml",
+					Status:   test.StatusPassed,
+					Duration: time.Second,
+					Warnings: []string{"response validate: field 'elapsed': must less then '100'"},
+					Rules: []test.RuleResult{{
+						Validator: "json",
+						Index:     0,
+						Key:       "elapsed",
+						Expected:  "less '100'",
+						Actual:    "150",
+						Message:   "response validate: field 'elapsed': must less then '100'",
+						Severity:  "warning",
+					}},
+				}},
+			}}},
+			contains: []string{
+				"<details>\n<summary><span class=\"passed\">passed</span> <span class=\"warning\">warnings: 1</span> Создание отчета <small>1-create.yml, 1s</small></summary>",
+				`<div class="warning">warning: response validate: field &#39;elapsed&#39;: must less then &#39;100&#39;</div>`,
+				`<td><span class="warning">warning: response validate: field &#39;elapsed&#39;: must less then &#39;100&#39;</span></td>`,
+			},
+		},
+		{
+			Name: "Данные запроса и ответа экранируются",
+			results: Results{Groups: []test.GroupResult{{
+				Name: "/reports",
+				Cases: []test.CaseResult{{
+					Name:     "Создание отчета",
+					Status:   test.StatusPassed,
+					Request:  &test.RequestDetails{Method: "POST", URL: "http://localhost/api/reports", Body: `{"name":"<fuel>"}`},
+					Response: &test.ResponseDetails{Code: 201, Body: `{"id":5}`},
+				}},
+			}}},
+			contains: []string{`{&#34;name&#34;:&#34;&lt;fuel&gt;&#34;}`},
+			excludes: []string{"<fuel>"},
+		},
+		{
+			Name: "Ошибки конфигурации",
+			results: Results{
+				Errors: []error{errors.New("parsing test file 'auth/1-login.yml': yaml: line 3: did not find expected key")},
+			},
+			contains: []string{
+				`<span class="errored">errored: 1</span>`,
+				`<h2>errors</h2>`,
+				"<details open>\n<summary><span class=\"errored\">errored</span> parsing test file &#39;auth/1-login.yml&#39;: yaml: line 3: did not find expected key",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := HTML{}.Write(&buf, c.results)
+
+		assert.Nil(t, err, c.Name)
+		for _, s := range c.contains {
+			assert.Contains(t, buf.String(), s, c.Name)
+		}
+		for _, s := range c.excludes {
+			assert.NotContains(t, buf.String(), s, c.Name)
+		}
+	}
+}
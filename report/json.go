@@ -0,0 +1,123 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// JSON - машиночитаемый отчет со всеми результатами, включая итоги проверки правил
+type JSON struct{}
+
+type jsonReport struct {
+	Summary jsonSummary `json:"summary"`
+	Groups  []jsonGroup `json:"groups"`
+}
+
+type jsonSummary struct {
+	Passed     int   `json:"passed"`
+	Failed     int   `json:"failed"`
+	Skipped    int   `json:"skipped"`
+	Errored    int   `json:"errored"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+type jsonGroup struct {
+	Name    string      `json:"name"`
+	Summary jsonSummary `json:"summary"`
+	Cases   []jsonCase  `json:"cases"`
+}
+
+type jsonCase struct {
+	Name       string                `json:"name"`
+	File       string                `json:"file"`
+	Status     test.Status           `json:"status"`
+	DurationMs int64                 `json:"duration_ms"`
+	Errors     []string              `json:"errors,omitempty"`
+	Warnings   []string              `json:"warnings,omitempty"`
+	Request    *test.RequestDetails  `json:"request,omitempty"`
+	Response   *test.ResponseDetails `json:"response,omitempty"`
+	Message    *string               `json:"message,omitempty"`
+	LatencyMs  map[string]float64    `json:"latency_ms,omitempty"`
+	Rules      []jsonRule            `json:"rules,omitempty"`
+}
+
+type jsonRule struct {
+	Validator string `json:"validator"`
+	Index     int    `json:"index"`
+	Key       string `json:"key,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+	Passed    bool   `json:"passed"`
+	Message   string `json:"message,omitempty"`
+	Severity  string `json:"severity,omitempty"`
+}
+
+func (JSON) Write(w io.Writer, results Results) error {
+	var report jsonReport
+
+	groups := results.groups()
+	for _, group := range groups {
+		jg := jsonGroup{
+			Name:    group.Name,
+			Summary: summary([]test.GroupResult{group}, group.Duration),
+			Cases:   make([]jsonCase, 0, len(group.Cases)),
+		}
+
+		for _, c := range group.Cases {
+			jc := jsonCase{
+				Name:       c.Name,
+				File:       c.Filename,
+				Status:     c.Status,
+				DurationMs: c.Duration.Milliseconds(),
+				Errors:     c.Errors,
+				Warnings:   c.Warnings,
+				Request:    c.Request,
+				Response:   c.Response,
+				Message:    c.Message,
+				LatencyMs:  c.Latency,
+			}
+
+			for _, rule := range c.Rules {
+				jc.Rules = append(jc.Rules, jsonRule{
+					Validator: rule.Validator,
+					Index:     rule.Index,
+					Key:       rule.Key,
+					Expected:  rule.Expected,
+					Actual:    rule.Actual,
+					Passed:    rule.Passed,
+					Message:   rule.Message,
+					Severity:  rule.Severity,
+				})
+			}
+
+			jg.Cases = append(jg.Cases, jc)
+		}
+
+		report.Groups = append(report.Groups, jg)
+	}
+	report.Summary = summary(groups, results.Duration)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(report)
+}
+
+func summary(groups []test.GroupResult, duration time.Duration) jsonSummary {
+	s := jsonSummary{
+		DurationMs: duration.Milliseconds(),
+	}
+
+	for _, group := range groups {
+		s.Passed += group.Count(test.StatusPassed)
+		s.Failed += group.Count(test.StatusFailed)
+		s.Skipped += group.Count(test.StatusSkipped)
+		s.Errored += group.Count(test.StatusErrored)
+	}
+
+	return s
+}
@@ -0,0 +1,375 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+func TestJSON(t *testing.T) {
+	message := `{"event":"report.ready"}`
+
+	cases := []struct {
+		Name    string
+		results Results
+		expect  string
+	}{
+		{
+			Name: "Прошедший тест с запросом, ответом и правилами",
+			results: Results{Duration: time.Second, Groups: []test.GroupResult{{
+				Name:     "/reports",
+				Duration: time.Second,
+				Cases: []test.CaseResult{{
+					Name:     "Создание отчета",
+					Filename: "1-create.yml",
+					Status:   test.StatusPassed,
+					Duration: time.Second,
+					Request: &test.RequestDetails{
+						Method:  "POST",
+						URL:     "http://localhost/api/reports",
+						Headers: map[string]string{"Content-Type": "application/json"},
+						Body:    `{"name":"<fuel>"}`,
+					},
+					Response: &test.ResponseDetails{
+						Code:    201,
+						Headers: map[string][]string{"Content-Type": {"application/json"}},
+						Body:    `{"id":5}`,
+					},
+					Message: &message,
+					Latency: map[string]float64{"total": 12.5, "ttfb": 10},
+					Rules: []test.RuleResult{
+						{Validator: "code", Index: 0, Expected: "equal '201'", Passed: true},
+						{Validator: "json", Index: 0, Key: "id", Expected: "type integer", Passed: true},
+					},
+				}},
+			}}},
+			expect: `{
+  "summary": {
+    "passed": 1,
+    "failed": 0,
+    "skipped": 0,
+    "errored": 0,
+    "duration_ms": 1000
+  },
+  "groups": [
+    {
+      "name": "/reports",
+      "summary": {
+        "passed": 1,
+        "failed": 0,
+        "skipped": 0,
+        "errored": 0,
+        "duration_ms": 1000
+      },
+      "cases": [
+        {
+          "name": "Создание отчета",
+          "file": "1-create.yml",
+          "status": "passed",
+          "duration_ms": 1000,
+          "request": {
+            "method": "POST",
+            "url": "http://localhost/api/reports",
+            "headers": {
+              "Content-Type": "application/json"
+            },
+            "body": "{\"name\":\"<fuel>\"}"
+          },
+          "response": {
+            "code": 201,
+            "headers": {
+              "Content-Type": [
+                "application/json"
+              ]
+            },
+            "body": "{\"id\":5}"
+          },
+          "message": "{\"event\":\"report.ready\"}",
+          "latency_ms": {
+            "total": 12.5,
+            "ttfb": 10
+          },
+          "rules": [
+            {
+              "validator": "code",
+              "index": 0,
+              "expected": "equal '201'",
+              "passed": true
+            },
+            {
+              "validator": "json",
+              "index": 0,
+              "key": "id",
+              "expected": "type integer",
+              "passed": true
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			Name: "Упавший и пропущенный тесты",
+			results: Results{Duration: 500 * time.Millisecond, Groups: []test.GroupResult{{
+				Name:     "/reports",
+				Duration: 500 * time.Millisecond,
+				Cases: []test.CaseResult{
+					{
+						Name:     "Получение отчета",
+						Filename: "2-get.yml",
+						Status:   test.StatusFailed,
+						Duration: 500 * time.Millisecond,
+						Errors:   []string{"response validate: field 'status': must be '2'"},
+						Rules: []test.RuleResult{{
+							Validator: "json",
+							Index:     0,
+							Key:       "status",
+							Expected:  "equal '2'",
+							Actual:    "1",
+							Message:   "response validate: field 'status': must be '2'",
+							Severity:  "error",
+						}},
+					},
+					{
+						Name:     "Удаление отчета",
+						Filename: "3-delete.yml",
+						Status:   test.StatusSkipped,
+						Errors:   []string{"previous test failed"},
+					},
+				},
+			}}},
+			expect: `{
+  "summary": {
+    "passed": 0,
+    "failed": 1,
+    "skipped": 1,
+    "errored": 0,
+    "duration_ms": 500
+  },
+  "groups": [
+    {
+      "name": "/reports",
+      "summary": {
+        "passed": 0,
+        "failed": 1,
+        "skipped": 1,
+        "errored": 0,
+        "duration_ms": 500
+      },
+      "cases": [
+        {
+          "name": "Получение отчета",
+          "file": "2-get.yml",
+          "status": "failed",
+          "duration_ms": 500,
+          "errors": [
+            "response validate: field 'status': must be '2'"
+          ],
+          "rules": [
+            {
+              "validator": "json",
+              "index": 0,
+              "key": "status",
+              "expected": "equal '2'",
+              "actual": "1",
+              "passed": false,
+              "message": "response validate: field 'status': must be '2'",
+              "severity": "error"
+            }
+          ]
+        },
+        {
+          "name": "Удаление отчета",
+          "file": "3-delete.yml",
+          "status": "skipped",
+          "duration_ms": 0,
+          "errors": [
+            "previous test failed"
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			Name: "Предупреждения",
+			results: Results{Duration: time.Second, Groups: []test.GroupResult{{
+				Name:     "/reports",
+				Duration: time.Second,
+				Cases: []test.CaseResult{{
+					Name:     "Создание отчета",
+					Filename: "1-create.yml",
+					Status:   test.StatusPassed,
+					Duration: time.Second,
+					Warnings: []string{"response validate: field 'elapsed': must less then '100'"},
+					Rules: []test.RuleResult{{
+						Validator: "json",
+						Index:     0,
+						Key:       "elapsed",
+						Expected:  "less '100'",
+						Actual:    "150",
+						Message:   "response validate: field 'elapsed': must less then '100'",
+						Severity:  "warning",
+					}},
+				}},
+			}}},
+			expect: `{
+  "summary": {
+    "passed": 1,
+    "failed": 0,
+    "skipped": 0,
+    "errored": 0,
+    "duration_ms": 1000
+  },
+  "groups": [
+    {
+      "name": "/reports",
+      "summary": {
+        "passed": 1,
+        "failed": 0,
+        "skipped": 0,
+        "errored": 0,
+        "duration_ms": 1000
+      },
+      "cases": [
+        {
+          "name": "Создание отчета",
+          "file": "1-create.yml",
+          "status": "passed",
+          "duration_ms": 1000,
+          "warnings": [
+            "response validate: field 'elapsed': must less then '100'"
+          ],
+          "rules": [
+            {
+              "validator": "json",
+              "index": 0,
+              "key": "elapsed",
+              "expected": "less '100'",
+              "actual": "150",
+              "passed": false,
+              "message": "response validate: field 'elapsed': must less then '100'",
+              "severity": "warning"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			Name: "Итоги по параллельным группам",
+			results: Results{Duration: 2500 * time.Millisecond, Groups: []test.GroupResult{
+				{Name: "/auth", Duration: time.Second, Cases: []test.CaseResult{{Name: "Вход", Status: test.StatusPassed}}},
+				{Name: "/users", Duration: 2 * time.Second, Cases: []test.CaseResult{{Name: "Список", Status: test.StatusErrored}}},
+			}},
+			expect: `{
+  "summary": {
+    "passed": 1,
+    "failed": 0,
+    "skipped": 0,
+    "errored": 1,
+    "duration_ms": 2500
+  },
+  "groups": [
+    {
+      "name": "/auth",
+      "summary": {
+        "passed": 1,
+        "failed": 0,
+        "skipped": 0,
+        "errored": 0,
+        "duration_ms": 1000
+      },
+      "cases": [
+        {
+          "name": "Вход",
+          "file": "",
+          "status": "passed",
+          "duration_ms": 0
+        }
+      ]
+    },
+    {
+      "name": "/users",
+      "summary": {
+        "passed": 0,
+        "failed": 0,
+        "skipped": 0,
+        "errored": 1,
+        "duration_ms": 2000
+      },
+      "cases": [
+        {
+          "name": "Список",
+          "file": "",
+          "status": "errored",
+          "duration_ms": 0
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+		{
+			Name: "Ошибки конфигурации",
+			results: Results{
+				Errors: []error{errors.New("parsing test file 'auth/1-login.yml': yaml: line 3: did not find expected key")},
+			},
+			expect: `{
+  "summary": {
+    "passed": 0,
+    "failed": 0,
+    "skipped": 0,
+    "errored": 1,
+    "duration_ms": 0
+  },
+  "groups": [
+    {
+      "name": "errors",
+      "summary": {
+        "passed": 0,
+        "failed": 0,
+        "skipped": 0,
+        "errored": 1,
+        "duration_ms": 0
+      },
+      "cases": [
+        {
+          "name": "parsing test file 'auth/1-login.yml': yaml: line 3: did not find expected key",
+          "file": "",
+          "status": "errored",
+          "duration_ms": 0,
+          "errors": [
+            "parsing test file 'auth/1-login.yml': yaml: line 3: did not find expected key"
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		err := JSON{}.Write(&buf, c.results)
+
+		assert.Nil(t, err, c.Name)
+		assert.Equal(t, c.expect, buf.String(), c.Name)
+	}
+}
@@ -0,0 +1,181 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// JUnit - отчет в формате JUnit XML.
+// Группа тестов становится testsuite, отдельный тест - testcase.
+// Формат понимают GitLab, Jenkins и большинство других CI.
+type JUnit struct{}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func (JUnit) Write(w io.Writer, results Results) error {
+	var suites junitSuites
+
+	for _, group := range results.groups() {
+		suite := junitSuite{
+			Name:     group.Name,
+			Tests:    len(group.Cases),
+			Failures: group.Count(test.StatusFailed),
+			Errors:   group.Count(test.StatusErrored),
+			Skipped:  group.Count(test.StatusSkipped),
+			Time:     seconds(group.Duration),
+		}
+
+		for _, c := range group.Cases {
+			suite.Cases = append(suite.Cases, junitTestCase(group, c))
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = seconds(results.Duration)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitTestCase(group test.GroupResult, c test.CaseResult) junitCase {
+	tc := junitCase{
+		Name:      c.Name,
+		Classname: group.Name,
+		File:      c.Filename,
+		Time:      seconds(c.Duration),
+		SystemOut: details(c),
+	}
+
+	message := strings.Join(c.Errors, "; ")
+
+	switch c.Status {
+	case test.StatusFailed:
+		tc.Failure = &junitMessage{Message: message, Text: strings.Join(c.Errors, "\n")}
+	case test.StatusErrored:
+		tc.Error = &junitMessage{Message: message, Text: strings.Join(c.Errors, "\n")}
+	case test.StatusSkipped:
+		tc.Skipped = &junitMessage{Message: message}
+	}
+
+	return tc
+}
+
+// details описывает запрос и ответ теста в виде текста
+func details(c test.CaseResult) string {
+	var b strings.Builder
+
+	if c.Request != nil {
+		fmt.Fprintf(&b, "> %s %s\n", c.Request.Method, c.Request.URL)
+		for _, k := range sortedKeys(c.Request.Headers) {
+			fmt.Fprintf(&b, "> %s: %s\n", k, c.Request.Headers[k])
+		}
+		if c.Request.Body != "" {
+			fmt.Fprintf(&b, ">\n%s\n", c.Request.Body)
+		}
+	}
+
+	if c.Response != nil {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "< %d\n", c.Response.Code)
+		for _, k := range sortedKeys(c.Response.Headers) {
+			for _, v := range c.Response.Headers[k] {
+				fmt.Fprintf(&b, "< %s: %s\n", k, v)
+			}
+		}
+		if c.Response.Body != "" {
+			fmt.Fprintf(&b, "<\n%s\n", c.Response.Body)
+		}
+	}
+
+	if len(c.Warnings) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		for _, warning := range c.Warnings {
+			fmt.Fprintf(&b, "warning: %s\n", warning)
+		}
+	}
+
+	if c.Message != nil {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "websocket message:\n%s\n", *c.Message)
+	}
+
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+func TestJUnit(t *testing.T) {
+	groups := []test.GroupResult{{
+		Name:     "/auth/login",
+		Duration: 1500 * time.Millisecond,
+		Cases: []test.CaseResult{
+			{
+				Name:     "Авторизация",
+				Filename: "1-login.yml",
+				Status:   test.StatusPassed,
+				Duration: time.Second,
+			},
+			{
+				Name:     "Проверка токена",
+				Filename: "2-check.yml",
+				Status:   test.StatusFailed,
+				Duration: 500 * time.Millisecond,
+				Errors:   []string{"http code validate: must be '200'"},
+				Request:  &test.RequestDetails{Method: "GET", URL: "http://localhost/api/user"},
+				Response: &test.ResponseDetails{Code: 401, Body: `{"success":false}`},
+			},
+			{
+				Name:     "Выход",
+				Filename: "3-logout.yml",
+				Status:   test.StatusSkipped,
+			},
+		},
+	}}
+
+	var buf bytes.Buffer
+	// Общее время - время всего запуска, а не сумма времени групп
+	err := JUnit{}.Write(&buf, Results{Groups: groups, Duration: 2 * time.Second})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1" errors="0" skipped="1" time="2.000">
+  <testsuite name="/auth/login" tests="3" failures="1" errors="0" skipped="1" time="1.500">
+    <testcase name="Авторизация" classname="/auth/login" file="1-login.yml" time="1.000"></testcase>
+    <testcase name="Проверка токена" classname="/auth/login" file="2-check.yml" time="0.500">
+      <failure message="http code validate: must be &#39;200&#39;">http code validate: must be &#39;200&#39;</failure>
+      <system-out>&gt; GET http://localhost/api/user&#xA;&#xA;&lt; 401&#xA;&lt;&#xA;{&#34;success&#34;:false}&#xA;</system-out>
+    </testcase>
+    <testcase name="Выход" classname="/auth/login" file="3-logout.yml" time="0.000">
+      <skipped></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
+
+func TestJUnitErrors(t *testing.T) {
+	results := Results{
+		Errors:   []error{errors.New("decoding test case 'tests/1-login.yml': yaml: line 2: did not find expected key")},
+		Duration: 10 * time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	err := JUnit{}.Write(&buf, results)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="1" failures="0" errors="1" skipped="0" time="0.010">
+  <testsuite name="errors" tests="1" failures="0" errors="1" skipped="0" time="0.000">
+    <testcase name="decoding test case &#39;tests/1-login.yml&#39;: yaml: line 2: did not find expected key" classname="errors" time="0.000">
+      <error message="decoding test case &#39;tests/1-login.yml&#39;: yaml: line 2: did not find expected key">decoding test case &#39;tests/1-login.yml&#39;: yaml: line 2: did not find expected key</error>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,141 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// Reporter - формирует отчет о результатах тестов в определенном формате
+type Reporter interface {
+	Write(w io.Writer, results Results) error
+}
+
+// ErrorsGroup - имя группы, в которую попадают ошибки конфигурации
+const ErrorsGroup = "errors"
+
+// Results - итоги запуска тестов
+type Results struct {
+	Groups []test.GroupResult
+	// Errors - ошибки конфигурации: не найдены тесты, не разобраны файлы и т.д.
+	Errors []error
+	// Duration - общее время запуска. Группы могут выполняться параллельно,
+	// поэтому это не сумма времени групп
+	Duration time.Duration
+}
+
+// groups возвращает группы для отчета.
+// Каждая ошибка конфигурации становится тестом с ошибкой в отдельной группе,
+// чтобы CI показал ее вместе с остальными результатами.
+func (r Results) groups() []test.GroupResult {
+	if len(r.Errors) == 0 {
+		return r.Groups
+	}
+
+	group := test.GroupResult{
+		Name:  ErrorsGroup,
+		Cases: make([]test.CaseResult, 0, len(r.Errors)),
+	}
+	for _, err := range r.Errors {
+		group.Cases = append(group.Cases, test.CaseResult{
+			Name:   err.Error(),
+			Status: test.StatusErrored,
+			Errors: []string{err.Error()},
+		})
+	}
+
+	groups := make([]test.GroupResult, 0, len(r.Groups)+1)
+	groups = append(groups, r.Groups...)
+
+	return append(groups, group)
+}
+
+// reporters - поддерживаемые форматы отчетов
+var reporters = map[string]Reporter{
+	"junit": JUnit{},
+	"json":  JSON{},
+	"html":  HTML{},
+}
+
+// Target - куда и в каком формате записать отчет
+type Target struct {
+	Format string
+	Path   string
+}
+
+// Targets - список отчетов из параметров запуска.
+// Реализует flag.Value, параметр можно указывать несколько раз: -report junit=report.xml
+type Targets []Target
+
+func (t *Targets) String() string {
+	parts := make([]string, 0, len(*t))
+	for _, target := range *t {
+		parts = append(parts, target.Format+"="+target.Path)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (t *Targets) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("report must be in format 'format=path', got '%s'", value)
+	}
+
+	format := strings.ToLower(parts[0])
+	if _, ok := reporters[format]; !ok {
+		return fmt.Errorf("unknown report format '%s', available: %s", parts[0], strings.Join(formats(), ", "))
+	}
+
+	*t = append(*t, Target{
+		Format: format,
+		Path:   parts[1],
+	})
+
+	return nil
+}
+
+// Write записывает все отчеты
+func Write(targets Targets, results Results) error {
+	for _, target := range targets {
+		if err := writeFile(target, results); err != nil {
+			return fmt.Errorf("writing %s report '%s': %w", target.Format, target.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFile(target Target, results Results) error {
+	reporter, ok := reporters[target.Format]
+	if !ok {
+		return fmt.Errorf("unknown report format")
+	}
+
+	f, err := os.Create(target.Path)
+	if err != nil {
+		return err
+	}
+
+	if err = reporter.Write(f, results); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func formats() []string {
+	list := make([]string, 0, len(reporters))
+	for format := range reporters {
+		list = append(list, format)
+	}
+	sort.Strings(list)
+
+	return list
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// syncBuffer - буфер для лога группы.
+// Пишут в него не только тесты, но и горутины websocket соединений,
+// поэтому запись защищена мьютексом.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// WriteTo переносит накопленный лог в w
+func (b *syncBuffer) WriteTo(w io.Writer) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.WriteTo(w)
+}
+
+// runParallel запускает группы в несколько потоков.
+// Каждая группа выполняется своим Runner со своим хранилищем,
+// лог группы копится в буфере и выводится в out целиком после её завершения,
+// чтобы строки разных групп не перемешивались. Run возвращается после закрытия
+// websocket соединений группы, поэтому их горутины уже ничего не допишут в буфер.
+// Результаты возвращаются в том же порядке, что и группы.
+func runParallel(groups []test.Group, parallel int, options test.Options, newLogger func(io.Writer) zerolog.Logger, out io.Writer) []test.GroupResult {
+	results := make([]test.GroupResult, len(groups))
+	jobs := make(chan int)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				buf := &syncBuffer{}
+				results[i] = test.NewRunner(newLogger(buf), options).Run(groups[i])
+
+				mu.Lock()
+				_, _ = buf.WriteTo(out)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range groups {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestRunParallel(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ws":
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+
+			_ = c.WriteMessage(websocket.TextMessage, []byte("hello"))
+			for {
+				if _, _, err = c.ReadMessage(); err != nil {
+					return
+				}
+			}
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	get := func(name, path string) test.Case {
+		code := "200"
+
+		return test.Case{
+			Name:     name,
+			Request:  test.Request{URL: srv.URL + path, Method: http.MethodGet},
+			Response: test.Response{Code: []rules.Rule{{Equal: &code}}},
+		}
+	}
+
+	groups := []test.Group{
+		{Name: "/slow", Tests: []test.Case{get("Долгий", "/slow"), get("Быстрый", "/")}},
+		{Name: "/ws", Tests: []test.Case{
+			{
+				Name:    "Подключение",
+				Request: test.Request{URL: "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws", Protocol: "ws", Channel: "main"},
+			},
+			get("Долгий", "/slow"),
+		}},
+		{Name: "/fail", Tests: []test.Case{get("Ошибка", "/fail")}},
+		{Name: "/fast", Tests: []test.Case{get("Быстрый", "/")}},
+	}
+
+	newLogger := func(out io.Writer) zerolog.Logger {
+		return zerolog.New(out)
+	}
+
+	var sequential []test.GroupResult
+	for _, parallel := range []int{1, 4} {
+		var out bytes.Buffer
+		results := runParallel(groups, parallel, test.Options{}, newLogger, &out)
+
+		// Результаты в порядке групп, а не в порядке завершения
+		if !assert.Len(t, results, len(groups)) {
+			continue
+		}
+		for i, group := range groups {
+			assert.Equal(t, group.Name, results[i].Name)
+		}
+
+		assert.Equal(t, test.StatusFailed, results[2].Cases[0].Status)
+		assert.Equal(t, 1, results[2].Count(test.StatusFailed))
+
+		// Лог каждой группы выводится одним блоком, вместе с сообщениями websocket соединения
+		var (
+			order []string
+			recv  bool
+		)
+		scanner := bufio.NewScanner(&out)
+		for scanner.Scan() {
+			var line struct {
+				Group   string `json:"group"`
+				Message string `json:"message"`
+			}
+			assert.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+
+			if line.Message == "recv: hello" {
+				assert.Equal(t, "/ws", line.Group)
+				recv = true
+			}
+			if len(order) == 0 || order[len(order)-1] != line.Group {
+				order = append(order, line.Group)
+			}
+		}
+		assert.True(t, recv)
+		assert.ElementsMatch(t, []string{"/slow", "/ws", "/fail", "/fast"}, order)
+
+		// Статусы не зависят от числа потоков
+		if sequential == nil {
+			sequential = results
+			continue
+		}
+		for i := range results {
+			for j := range results[i].Cases {
+				assert.Equal(t, sequential[i].Cases[j].Status, results[i].Cases[j].Status, results[i].Name)
+			}
+		}
+	}
+}
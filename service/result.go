@@ -0,0 +1,50 @@
+package service
+
+import (
+	"time"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+// Коды завершения процесса
+const (
+	// ExitPassed - все тесты прошли
+	ExitPassed = 0
+	// ExitFailed - часть тестов не прошла
+	ExitFailed = 1
+	// ExitErrored - ошибка в конфигурации или описании тестов
+	ExitErrored = 2
+)
+
+// Result - итог запуска всех тестов
+type Result struct {
+	Groups []test.GroupResult
+	// Errors - ошибки конфигурации: не найдены тесты, не разобраны файлы и т.д.
+	Errors []error
+	// Duration - общее время запуска
+	Duration time.Duration
+}
+
+// Count возвращает количество тестов во всех группах с указанным статусом
+func (r Result) Count(status test.Status) int {
+	var count int
+	for _, group := range r.Groups {
+		count += group.Count(status)
+	}
+
+	return count
+}
+
+// ExitCode возвращает код завершения процесса по итогам запуска.
+// Ошибки конфигурации важнее упавших тестов.
+func (r Result) ExitCode() int {
+	if len(r.Errors) > 0 || r.Count(test.StatusErrored) > 0 {
+		return ExitErrored
+	}
+
+	if r.Count(test.StatusFailed) > 0 {
+		return ExitFailed
+	}
+
+	return ExitPassed
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test"
+)
+
+func TestResultExitCode(t *testing.T) {
+	passed := test.GroupResult{Name: "/passed", Cases: []test.CaseResult{
+		{Name: "1", Status: test.StatusPassed},
+		{Name: "2", Status: test.StatusPassed},
+	}}
+	failed := test.GroupResult{Name: "/failed", Cases: []test.CaseResult{
+		{Name: "1", Status: test.StatusPassed},
+		{Name: "2", Status: test.StatusFailed},
+		{Name: "3", Status: test.StatusSkipped},
+	}}
+	errored := test.GroupResult{Name: "/errored", Cases: []test.CaseResult{
+		{Name: "1", Status: test.StatusErrored},
+		{Name: "2", Status: test.StatusSkipped},
+	}}
+
+	cases := []struct {
+		Name   string
+		Result Result
+		Expect int
+	}{
+		{
+			Name:   "Все тесты прошли",
+			Result: Result{Groups: []test.GroupResult{passed}},
+			Expect: ExitPassed,
+		},
+		{
+			Name:   "Часть тестов упала",
+			Result: Result{Groups: []test.GroupResult{passed, failed}},
+			Expect: ExitFailed,
+		},
+		{
+			Name:   "Тест описан с ошибкой",
+			Result: Result{Groups: []test.GroupResult{failed, errored}},
+			Expect: ExitErrored,
+		},
+		{
+			Name:   "Ошибка конфигурации",
+			Result: Result{Groups: []test.GroupResult{passed}, Errors: []error{fmt.Errorf("decoding test case")}},
+			Expect: ExitErrored,
+		},
+	}
+
+	for _, curCase := range cases {
+		assert.Equal(t, curCase.Expect, curCase.Result.ExitCode(), curCase.Name)
+	}
+
+	all := Result{Groups: []test.GroupResult{passed, failed, errored}}
+	assert.Equal(t, 3, all.Count(test.StatusPassed))
+	assert.Equal(t, 1, all.Count(test.StatusFailed))
+	assert.Equal(t, 2, all.Count(test.StatusSkipped))
+	assert.Equal(t, 1, all.Count(test.StatusErrored))
+}
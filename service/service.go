@@ -1,37 +1,81 @@
 package service
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	"github.com/MashinaMashina/api-tests/finder"
 	"github.com/MashinaMashina/api-tests/test"
+	"github.com/MashinaMashina/api-tests/test/validators/openapi"
 )
 
+// Config - параметры запуска тестов
+type Config struct {
+	// Dir - папка с тестами
+	Dir string
+	// Pattern - регулярное выражение для отбора групп по имени
+	Pattern string
+	// ContinueOnFailure - продолжать выполнение группы после упавшего теста
+	ContinueOnFailure bool
+	// Parallel - сколько групп можно выполнять одновременно.
+	// Значение меньше 2 означает последовательный запуск.
+	Parallel int
+	// OpenAPI - путь к спецификации OpenAPI 3, по которой проверяются все HTTP запросы
+	OpenAPI string
+	// UpdateSnapshots - перезаписать снимки ответов вместо сравнения с ними
+	UpdateSnapshots bool
+	// NewLogger создает логгер, который пишет в out.
+	// Используется при параллельном запуске, чтобы буферизировать лог каждой группы.
+	NewLogger func(out io.Writer) zerolog.Logger
+}
+
 // Run - запускает все тесты.
 // Вначале собирает информацию о всех тестах в группы,
 // а после запускает группы.
-func Run(dir, patternStr string) {
-	groups := finder.Find(dir, "")
+func Run(cfg Config) (result Result) {
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+	}()
+
+	groups, errs := finder.Find(cfg.Dir, "")
+	for _, err := range errs {
+		log.Error().Err(err).Msg("loading tests")
+	}
+	result.Errors = append(result.Errors, errs...)
 
 	if len(groups) == 0 {
-		absPath, err := filepath.Abs(dir)
+		absPath, err := filepath.Abs(cfg.Dir)
 		if err != nil {
-			log.Error().Err(err).Msgf("read tests directory '%s'", dir)
+			err = fmt.Errorf("read tests directory '%s': %w", cfg.Dir, err)
 		} else {
-			log.Error().Msgf("not found tests in '%s'", absPath)
+			err = fmt.Errorf("not found tests in '%s'", absPath)
 		}
 
-		return
+		log.Error().Err(err).Send()
+		result.Errors = append(result.Errors, err)
+
+		return result
 	}
 
-	if patternStr != "" {
-		pattern, err := regexp.Compile(patternStr)
+	// Глобальные группы выполняются всегда, независимо от шаблона
+	var globals []test.Group
+	globals, groups = splitGlobal(groups)
+
+	if cfg.Pattern != "" {
+		pattern, err := regexp.Compile(cfg.Pattern)
 		if err != nil {
 			log.Error().Err(err).Msgf("compile pattern")
-			return
+			result.Errors = append(result.Errors, fmt.Errorf("compile pattern: %w", err))
+
+			return result
 		}
 
 		newGroups := make([]test.Group, 0, len(groups))
@@ -43,17 +87,80 @@ func Run(dir, patternStr string) {
 		groups = newGroups
 	}
 
-	var errors, success int
-	runner := test.NewRunner()
-	for _, group := range groups {
-		errors, success = runner.Run(group)
+	// Спецификацию из параметров запуска проверяем сразу, без неё запуск не имеет смысла
+	if cfg.OpenAPI != "" {
+		if _, err := openapi.Load(cfg.OpenAPI); err != nil {
+			log.Error().Err(err).Msg("loading openapi spec")
+			result.Errors = append(result.Errors, err)
+
+			return result
+		}
+	}
+
+	options := test.Options{
+		ContinueOnFailure: cfg.ContinueOnFailure,
+		OpenAPI:           cfg.OpenAPI,
+		UpdateSnapshots:   cfg.UpdateSnapshots,
 	}
 
-	logger := log.With().Int("errors", errors).Int("success", success).Logger()
+	// Глобальные группы выполняются последовательно до остальных,
+	// их переменные передаются всем следующим группам
+	for _, group := range globals {
+		groupResult, vars := test.NewRunner(log.Logger, options).RunGlobal(group)
+		result.Groups = append(result.Groups, groupResult)
+
+		options.Globals = mergeVars(options.Globals, vars)
+		if groupResult.Count(test.StatusFailed) > 0 || groupResult.Count(test.StatusErrored) > 0 {
+			options.GlobalsFailed = true
+		}
+	}
 
-	if errors > 0 {
+	if cfg.Parallel > 1 && cfg.NewLogger != nil {
+		result.Groups = runParallel(groups, cfg.Parallel, options, cfg.NewLogger, os.Stdout)
+	} else {
+		runner := test.NewRunner(log.Logger, options)
+		for _, group := range groups {
+			result.Groups = append(result.Groups, runner.Run(group))
+		}
+	}
+
+	logger := log.With().
+		Int("passed", result.Count(test.StatusPassed)).
+		Int("failed", result.Count(test.StatusFailed)).
+		Int("skipped", result.Count(test.StatusSkipped)).
+		Int("errored", result.Count(test.StatusErrored)).
+		Logger()
+
+	if result.ExitCode() != ExitPassed {
 		logger.Error().Msg("tests failed")
 	} else {
 		logger.Info().Msg("all tests passed")
 	}
+
+	return result
+}
+
+// splitGlobal отделяет группы глобальной подготовки от остальных
+func splitGlobal(groups []test.Group) (globals []test.Group, others []test.Group) {
+	for _, group := range groups {
+		if group.Init.Global {
+			globals = append(globals, group)
+		} else {
+			others = append(others, group)
+		}
+	}
+
+	return globals, others
+}
+
+func mergeVars(base, vars map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(vars))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+
+	return merged
 }
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// funcs - функции, доступные в шаблонах.
+// Функция urlquery есть в text/template по-умолчанию.
+// now отбрасывает показания монотонных часов, иначе {{now}} выводится с суффиксом "m=+...".
+var funcs = template.FuncMap{
+	"uuid":       uuid,
+	"now":        func() time.Time { return time.Now().Round(0) },
+	"date":       date,
+	"offset":     offset,
+	"unix":       func(t time.Time) int64 { return t.Unix() },
+	"unixMilli":  func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) },
+	"randInt":    randInt,
+	"randString": randString,
+	"base64":     func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+	"sha256":     func(s string) string { return hash(sha256.Sum256([]byte(s))) },
+	"hmac":       hmacSHA256,
+	"jsonEscape": jsonEscape,
+	"json":       toJSON,
+	"env":        os.Getenv,
+	"default":    defaultValue,
+}
+
+// uuid генерирует случайный UUID версии 4
+func uuid() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// date форматирует время по шаблону из пакета time, например: {{now | date "2006-01-02"}}.
+// Так же понимает названия rfc3339 и date.
+func date(layout string, t time.Time) string {
+	switch strings.ToLower(layout) {
+	case "rfc3339":
+		layout = time.RFC3339
+	case "date":
+		layout = "2006-01-02"
+	}
+
+	return t.Format(layout)
+}
+
+// offset сдвигает время на указанный интервал, например: {{now | offset "-24h"}}.
+// Кроме единиц из time.ParseDuration поддерживаются дни: "7d", "-1d".
+func offset(duration string, t time.Time) (time.Time, error) {
+	if strings.HasSuffix(duration, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(duration, "d"))
+		if err != nil {
+			return t, fmt.Errorf("parsing days offset '%s': %w", duration, err)
+		}
+
+		return t.AddDate(0, 0, days), nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return t, fmt.Errorf("parsing offset: %w", err)
+	}
+
+	return t.Add(d), nil
+}
+
+// randInt возвращает случайное число от min до max включительно
+func randInt(min, max int) (int, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt: max %d less than min %d", max, min)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max-min)+1))
+	if err != nil {
+		return 0, err
+	}
+
+	return min + int(n.Int64()), nil
+}
+
+const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randString возвращает случайную строку из латинских букв и цифр
+func randString(length int) (string, error) {
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(letters))))
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = letters[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+func hash(sum [sha256.Size]byte) string {
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 возвращает HMAC-SHA256 подпись в hex, например: {{.body | hmac "secret"}}
+func hmacSHA256(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// jsonEscape экранирует строку для вставки внутрь JSON строки, кавычки по краям не добавляются
+func jsonEscape(s string) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b[1 : len(b)-1]), nil
+}
+
+// toJSON кодирует значение в JSON, например, сохраненный объект: {{json .user}}
+func toJSON(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// defaultValue возвращает def, если value пустое, например: {{.page | default 1}}
+func defaultValue(def interface{}, value interface{}) interface{} {
+	if value == nil {
+		return def
+	}
+
+	v := reflect.ValueOf(value)
+	if v.IsZero() {
+		return def
+	}
+
+	return value
+}
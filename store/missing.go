@@ -0,0 +1,109 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+)
+
+// MissingError - шаблон использует переменные, которых нет в хранилище.
+// Обычно это значит, что не выполнился тест, который должен был их сохранить.
+type MissingError struct {
+	Names []string
+}
+
+func (e *MissingError) Error() string {
+	return fmt.Sprintf("missing variables: %s", strings.Join(e.Names, ", "))
+}
+
+// missingVars возвращает имена переменных из шаблона, которых нет в хранилище.
+// Переменные в условиях if и with, а так же в выражениях с default не считаются обязательными -
+// для них отсутствие значения предусмотрено.
+func (s *Store) missingVars(tree *parse.Tree) []string {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+
+	names := make(map[string]struct{})
+	s.collectMissing(tree.Root, true, names)
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// collectMissing обходит узлы шаблона. dotIsRoot - указывает ли точка на хранилище,
+// внутри range и with точка указывает на другое значение.
+func (s *Store) collectMissing(node parse.Node, dotIsRoot bool, names map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			s.collectMissing(child, dotIsRoot, names)
+		}
+	case *parse.ActionNode:
+		s.collectMissing(n.Pipe, dotIsRoot, names)
+	case *parse.TemplateNode:
+		s.collectMissing(n.Pipe, dotIsRoot, names)
+	case *parse.IfNode:
+		s.collectMissing(n.List, dotIsRoot, names)
+		s.collectMissing(n.ElseList, dotIsRoot, names)
+	case *parse.WithNode:
+		s.collectMissing(n.List, false, names)
+		s.collectMissing(n.ElseList, dotIsRoot, names)
+	case *parse.RangeNode:
+		s.collectMissing(n.Pipe, dotIsRoot, names)
+		s.collectMissing(n.List, false, names)
+		s.collectMissing(n.ElseList, dotIsRoot, names)
+	case *parse.PipeNode:
+		if n == nil || hasDefault(n) {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			s.collectMissing(cmd, dotIsRoot, names)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			s.collectMissing(arg, dotIsRoot, names)
+		}
+	case *parse.ChainNode:
+		s.collectMissing(n.Node, dotIsRoot, names)
+	case *parse.FieldNode:
+		if dotIsRoot {
+			s.checkVar(n.Ident[0], names)
+		}
+	case *parse.VariableNode:
+		// $.name всегда указывает на хранилище
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			s.checkVar(n.Ident[1], names)
+		}
+	}
+}
+
+func (s *Store) checkVar(name string, names map[string]struct{}) {
+	if _, ok := s.data[name]; !ok {
+		names[name] = struct{}{}
+	}
+}
+
+// hasDefault проверяет, вызывается ли в выражении функция default
+func hasDefault(pipe *parse.PipeNode) bool {
+	for _, cmd := range pipe.Cmds {
+		if len(cmd.Args) == 0 {
+			continue
+		}
+
+		if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && ident.Ident == "default" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -11,13 +11,20 @@ import (
 // Store - хранилище переменных.
 // Используется для передачи данных от одного теста к другому и
 // для конфигурирования тестов через единый файл инициализации.
+// Значения типизированы: кроме строк хранятся числа, bool, объекты и массивы,
+// поэтому в шаблонах можно обращаться к полям {{.user.id}} и перебирать {{range .items}}.
+// Объекты и массивы хранятся как Object и Array и без функций выводятся как JSON.
 type Store struct {
-	data map[string]string
+	data map[string]interface{}
+	// readOnly - переменные, которые нельзя перезаписать, например, глобальные
+	readOnly map[string]struct{}
+	// saved - переменные, сохраненные тестами через Set
+	saved map[string]struct{}
 }
 
 // NewStore создает объект хранилища
-func NewStore(init map[string]string) *Store {
-	vars := make(map[string]string)
+func NewStore(init map[string]interface{}) *Store {
+	vars := make(map[string]interface{})
 
 	// Все переменные окружения, которые начинаются на TESTS_ добавляем в список
 	envs := os.Environ()
@@ -33,38 +40,101 @@ func NewStore(init map[string]string) *Store {
 	}
 
 	for k := range init {
-		vars[k] = init[k]
+		vars[k] = wrap(init[k])
 	}
 
 	return &Store{
-		data: vars,
+		data:     vars,
+		readOnly: make(map[string]struct{}),
+		saved:    make(map[string]struct{}),
 	}
 }
 
-// Set - устанавливает значение переменной
-func (s *Store) Set(k, v string) {
+// SetReadOnly устанавливает значения переменных и запрещает их изменять.
+// Используется для глобальных переменных, общих для всех групп.
+func (s *Store) SetReadOnly(vars map[string]interface{}) {
+	for k, v := range vars {
+		s.data[k] = wrap(v)
+		s.readOnly[k] = struct{}{}
+	}
+}
+
+// Set - устанавливает значение переменной, сохраненной тестом.
+// Возвращает ошибку, если переменная доступна только для чтения.
+func (s *Store) Set(k string, v interface{}) error {
+	if _, ok := s.readOnly[k]; ok {
+		return fmt.Errorf("variable '%s' is read-only", k)
+	}
+
+	s.data[k] = wrap(v)
+	s.saved[k] = struct{}{}
+
+	return nil
+}
+
+// SetService устанавливает служебную переменную, например замер времени запроса.
+// Служебные переменные не попадают в Saved и перезаписываются всегда.
+func (s *Store) SetService(k string, v interface{}) {
 	s.data[k] = v
+	delete(s.saved, k)
+}
+
+// Copy возвращает независимую копию хранилища.
+// Изменения копии не попадают в исходное хранилище.
+func (s *Store) Copy() *Store {
+	c := &Store{
+		data:     make(map[string]interface{}, len(s.data)),
+		readOnly: make(map[string]struct{}, len(s.readOnly)),
+		saved:    make(map[string]struct{}, len(s.saved)),
+	}
+	for k, v := range s.data {
+		c.data[k] = v
+	}
+	for k := range s.readOnly {
+		c.readOnly[k] = struct{}{}
+	}
+	for k := range s.saved {
+		c.saved[k] = struct{}{}
+	}
+
+	return c
 }
 
 // Get - получает значение переменной.
 // Так же, вторым значением сообщает о наличии переменной в хранилище
-func (s *Store) Get(k string) (string, bool) {
+func (s *Store) Get(k string) (interface{}, bool) {
 	val, ok := s.data[k]
 	return val, ok
 }
 
+// Saved возвращает копию переменных, сохраненных тестами через Set.
+// Переменные из init файлов, окружения и служебные переменные не возвращаются.
+func (s *Store) Saved() map[string]interface{} {
+	vars := make(map[string]interface{}, len(s.saved))
+	for k := range s.saved {
+		vars[k] = s.data[k]
+	}
+
+	return vars
+}
+
 // Replace принимает на вход шаблон в виде строки
 // и заменяет в ней переменные данными из хранилища.
 // Используется формат из стандартной библиотеки text/template.
 // Пример входного шаблона: 'Привет, {{.name}}!' - тут используется переменная name.
+// Если в шаблоне есть переменные, которых нет в хранилище, возвращается *MissingError.
 func (s *Store) Replace(pattern string) (string, error) {
-	t := template.New("")
+	t := template.New("").Funcs(funcs)
 	_, err := t.Parse(pattern)
 
 	if err != nil {
 		return "", fmt.Errorf("parsing: %w", err)
 	}
 
+	if missing := s.missingVars(t.Tree); len(missing) > 0 {
+		return "", &MissingError{Names: missing}
+	}
+
 	var buf bytes.Buffer
 	err = t.Execute(&buf, s.data)
 
@@ -1,14 +1,17 @@
 package store
 
 import (
+	"encoding/json"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEmptyStore(t *testing.T) {
 	store := NewStore(nil)
-	store.Set("a", "NEW A")
+	assert.Nil(t, store.Set("a", "NEW A"))
 	res, err := store.Replace("ab{{.a}}bb")
 
 	assert.Nil(t, err)
@@ -16,12 +19,189 @@ func TestEmptyStore(t *testing.T) {
 }
 
 func TestFilledStore(t *testing.T) {
-	store := NewStore(map[string]string{
+	store := NewStore(map[string]interface{}{
 		"b": "NEW B",
 	})
-	store.Set("a", "NEW A")
+	assert.Nil(t, store.Set("a", "NEW A"))
 	res, err := store.Replace("ab{{.a}}b{{.b}}b")
 
 	assert.Nil(t, err)
 	assert.Equal(t, "abNEW AbNEW Bb", res)
 }
+
+func TestMissingVariables(t *testing.T) {
+	store := NewStore(map[string]interface{}{
+		"a": "A",
+	})
+
+	_, err := store.Replace("{{.a}}{{.b}}{{range .c}}{{.inner}}{{$.d}}{{end}}")
+
+	var missing *MissingError
+	if assert.ErrorAs(t, err, &missing) {
+		assert.Equal(t, []string{"b", "c", "d"}, missing.Names)
+	}
+
+	res, err := store.Replace("{{if .b}}yes{{else}}no b{{end}}{{with .c}}{{.inner}}{{end}}")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "no b", res)
+}
+
+func TestReadOnly(t *testing.T) {
+	store := NewStore(map[string]interface{}{
+		"token": "local",
+	})
+	store.SetReadOnly(map[string]interface{}{
+		"token": "global",
+	})
+
+	assert.EqualError(t, store.Set("token", "new"), "variable 'token' is read-only")
+	assert.Nil(t, store.Set("other", "value"))
+
+	res, err := store.Replace("{{.token}} {{.other}}")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "global value", res)
+	assert.Equal(t, map[string]interface{}{"other": "value"}, store.Saved())
+}
+
+func TestSaved(t *testing.T) {
+	t.Setenv("TESTS_HOST", "env")
+	store := NewStore(map[string]interface{}{
+		"host": "init",
+	})
+
+	assert.Nil(t, store.Set("id", 5))
+	store.SetService("latency_total", 10.5)
+	store.SetService("latency_total", 20.5)
+
+	latency, _ := store.Get("latency_total")
+	assert.Equal(t, 20.5, latency)
+	assert.Equal(t, map[string]interface{}{"id": 5}, store.Saved())
+}
+
+func TestCopy(t *testing.T) {
+	store := NewStore(map[string]interface{}{"host": "localhost"})
+	store.SetReadOnly(map[string]interface{}{"token": "abc"})
+	assert.Nil(t, store.Set("id", "1"))
+
+	c := store.Copy()
+	assert.Nil(t, c.Set("id", "2"))
+	assert.Nil(t, c.Set("name", "Ivan"))
+	assert.EqualError(t, c.Set("token", "def"), "variable 'token' is read-only")
+
+	// Исходное хранилище не изменилось
+	assert.Equal(t, map[string]interface{}{"id": "1"}, store.Saved())
+	assert.Equal(t, map[string]interface{}{"id": "2", "name": "Ivan"}, c.Saved())
+}
+
+func TestFuncs(t *testing.T) {
+	t.Setenv("API_TESTS_FUNCS", "env value")
+	store := NewStore(map[string]interface{}{
+		"login": "ivan",
+		"empty": "",
+	})
+
+	cases := []struct {
+		Pattern string
+		Expect  string
+	}{
+		{Pattern: `{{base64 "ivan:qwerty"}}`, Expect: "aXZhbjpxd2VydHk="},
+		{Pattern: `{{sha256 .login}}`, Expect: "cd0b9452fc376fc4c35a60087b366f70d883fc901524daf1f122fbd319384f6a"},
+		{Pattern: `{{.login | hmac "secret"}}`, Expect: "65311d7c5411611eb060b2be283b0b28cdbeef3a404c4084cf52a91e879f45e2"},
+		{Pattern: `{{jsonEscape "a \"b\"\n"}}`, Expect: `a \"b\"\n`},
+		{Pattern: `{{env "API_TESTS_FUNCS"}}`, Expect: "env value"},
+		{Pattern: `{{.empty | default "none"}} {{default "guest" .unknown}}`, Expect: "none guest"},
+		{Pattern: `{{urlquery "a b&c"}}`, Expect: "a+b%26c"},
+		{Pattern: `{{len (randString 12)}}`, Expect: "12"},
+		{Pattern: `{{$n := randInt 5 5}}{{$n}}`, Expect: "5"},
+	}
+
+	for _, curCase := range cases {
+		res, err := store.Replace(curCase.Pattern)
+
+		assert.Nil(t, err, curCase.Pattern)
+		assert.Equal(t, curCase.Expect, res, curCase.Pattern)
+	}
+
+	res, err := store.Replace(`{{uuid}}`)
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", res)
+
+	// Без суффикса монотонных часов "m=+..."
+	res, err = store.Replace(`{{now}}`)
+	assert.Nil(t, err)
+	assert.NotContains(t, res, "m=")
+
+	res, err = store.Replace(`{{now | offset "-1d" | date "date"}}`)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Now().AddDate(0, 0, -1).Format("2006-01-02"), res)
+
+	res, err = store.Replace(`{{now | offset "1h" | unixMilli}}`)
+	assert.Nil(t, err)
+	assert.InDelta(t, time.Now().Add(time.Hour).UnixNano()/int64(time.Millisecond), mustAtoi(t, res), 5000)
+}
+
+func mustAtoi(t *testing.T, s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	assert.Nil(t, err)
+
+	return n
+}
+
+func TestTypedValues(t *testing.T) {
+	store := NewStore(map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":   json.Number("1663099200000"),
+			"name": "Ivan",
+		},
+		"items":  []interface{}{"a", "b"},
+		"active": true,
+	})
+
+	res, err := store.Replace(`{{.user.id}} {{.user.name}} {{range .items}}{{.}}{{end}} {{.active}} {{json .user}}`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `1663099200000 Ivan ab true {"id":1663099200000,"name":"Ivan"}`, res)
+}
+
+func TestNumberCompare(t *testing.T) {
+	store := NewStore(nil)
+	assert.Nil(t, store.Set("id", json.Number("5")))
+	assert.Nil(t, store.Set("price", json.Number("1.5")))
+	assert.Nil(t, store.Set("user", map[string]interface{}{"age": json.Number("18")}))
+
+	res, err := store.Replace(`{{if eq .id 5}}id{{end}} {{if eq .price 1.5}}price{{end}} {{if ge .user.age 18}}adult{{end}} {{.price}}`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `id price adult 1.5`, res)
+}
+
+func TestObjectOutput(t *testing.T) {
+	store := NewStore(nil)
+	assert.Nil(t, store.Set("user", map[string]interface{}{
+		"id":      json.Number("5"),
+		"profile": map[string]interface{}{"city": "Москва"},
+		"roles":   []interface{}{"admin", "user"},
+	}))
+	assert.Nil(t, store.Set("items", []interface{}{map[string]interface{}{"name": "a"}}))
+
+	cases := []struct {
+		Pattern string
+		Expect  string
+	}{
+		// Без функций объекты и массивы выводятся как JSON
+		{Pattern: `{{.user}}`, Expect: `{"id":5,"profile":{"city":"Москва"},"roles":["admin","user"]}`},
+		{Pattern: `{{json .user}}`, Expect: `{"id":5,"profile":{"city":"Москва"},"roles":["admin","user"]}`},
+		{Pattern: `{{.user.profile}}`, Expect: `{"city":"Москва"}`},
+		{Pattern: `{{.user.roles}}`, Expect: `["admin","user"]`},
+		{Pattern: `{{.items}}`, Expect: `[{"name":"a"}]`},
+		{Pattern: `{{.user.id}} {{index .user.roles 1}} {{range .items}}{{.name}}{{end}} {{len .user.roles}}`, Expect: `5 user a 2`},
+	}
+
+	for _, c := range cases {
+		res, err := store.Replace(c.Pattern)
+		assert.Nil(t, err, c.Pattern)
+		assert.Equal(t, c.Expect, res, c.Pattern)
+	}
+}
@@ -0,0 +1,57 @@
+package store
+
+import "encoding/json"
+
+// Object - объект из JSON ответа или init файла.
+// В шаблоне {{.user}} выводится как JSON, как и до типизации хранилища,
+// а к полям можно обращаться как к полям map: {{.user.id}}.
+type Object map[string]interface{}
+
+// Array - массив из JSON ответа или init файла. В шаблоне {{.items}} выводится как JSON.
+type Array []interface{}
+
+func (o Object) String() string {
+	return jsonString(o)
+}
+
+func (a Array) String() string {
+	return jsonString(a)
+}
+
+func jsonString(value interface{}) string {
+	s, err := toJSON(value)
+	if err != nil {
+		return err.Error()
+	}
+
+	return s
+}
+
+// wrap заменяет объекты и массивы, в том числе вложенные, на Object и Array,
+// а числа json.Number - на int64 или float64, чтобы их можно было сравнивать в шаблонах: {{if eq .id 5}}
+func wrap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v
+	case map[string]interface{}:
+		obj := make(Object, len(v))
+		for k, item := range v {
+			obj[k] = wrap(item)
+		}
+		return obj
+	case []interface{}:
+		arr := make(Array, len(v))
+		for i, item := range v {
+			arr[i] = wrap(item)
+		}
+		return arr
+	}
+
+	return value
+}
@@ -7,36 +7,66 @@ import (
 
 // Case - описание отдельного теста
 type Case struct {
-	Filename string
-	Name     string                      `yaml:"name"`
-	Request  Request                     `yaml:"request"`
-	Response Response                    `yaml:"response"`
-	Message  []validators.ValidatorDescr `yaml:"message"` // только если Protocol==ws
-	Receive  Receive                     `yaml:"receive"`
+	Filename string                      `yaml:"-"`
+	Dir      string                      `yaml:"-"` // папка файла теста, в ней хранятся снимки ответов
+	Name     string                      `yaml:"name,omitempty"`
+	Request  Request                     `yaml:"request,omitempty"`
+	Response Response                    `yaml:"response,omitempty"`
+	Message  []validators.ValidatorDescr `yaml:"message,omitempty"` // только если Protocol==ws
+	Receive  Receive                     `yaml:"receive,omitempty"`
+	Retry    Retry                       `yaml:"retry,omitempty"`
+	Snapshot *Snapshot                   `yaml:"snapshot,omitempty"`
 }
 
 // Request - описание запроса
 type Request struct {
-	URL      string            `yaml:"url"`
-	Method   string            `yaml:"method"`
-	Body     string            `yaml:"body"`
-	Timeout  string            `yaml:"timeout"` // string, тк может быть с переменными. В мс
-	Headers  map[string]string `yaml:"headers"`
-	Protocol string            `yaml:"protocol"`
-	Channel  string            `yaml:"channel"` // только если Protocol==ws
+	URL       string            `yaml:"url,omitempty"`
+	Method    string            `yaml:"method,omitempty"`
+	Body      string            `yaml:"body,omitempty"`
+	BodyFile  string            `yaml:"body-file,omitempty"` // файл с телом запроса, содержимое шаблонизируется
+	Form      map[string]string `yaml:"form,omitempty"`      // тело application/x-www-form-urlencoded
+	Multipart []Part            `yaml:"multipart,omitempty"` // части тела multipart/form-data
+	Timeout   string            `yaml:"timeout,omitempty"`   // string, тк может быть с переменными. В мс
+	Headers   map[string]string `yaml:"headers,omitempty"`
+	Protocol  string            `yaml:"protocol,omitempty"`
+	Channel   string            `yaml:"channel,omitempty"` // только если Protocol==ws
+}
+
+// Part - часть multipart/form-data запроса: поле со значением или файл
+type Part struct {
+	Name        string `yaml:"name,omitempty"`
+	Value       string `yaml:"value,omitempty"`
+	File        string `yaml:"file,omitempty"`         // путь к файлу относительно файла теста
+	Filename    string `yaml:"filename,omitempty"`     // имя файла в запросе, по-умолчанию имя файла на диске
+	ContentType string `yaml:"content-type,omitempty"` // по-умолчанию определяется по расширению файла
 }
 
 // Response - описание валидации ответа
 type Response struct {
-	Headers []rules.Rule                `yaml:"headers"`
-	Latency []rules.Rule                `yaml:"latency"`
-	Code    []rules.Rule                `yaml:"code"`
-	Body    []validators.ValidatorDescr `yaml:"body"`
+	Headers []rules.Rule                `yaml:"headers,omitempty"`
+	Latency []rules.Rule                `yaml:"latency,omitempty"`
+	Code    []rules.Rule                `yaml:"code,omitempty"`
+	Body    []validators.ValidatorDescr `yaml:"body,omitempty"`
+}
+
+// Retry - описание повтора запроса, пока ответ не пройдет проверку.
+// Используется для ожидания асинхронных операций на сервере.
+type Retry struct {
+	Attempts string                      `yaml:"attempts,omitempty"` // string, тк может быть с переменными
+	Interval string                      `yaml:"interval,omitempty"` // пауза между попытками в секундах
+	Backoff  string                      `yaml:"backoff,omitempty"`  // во сколько раз увеличивать паузу после каждой попытки
+	Until    []validators.ValidatorDescr `yaml:"until,omitempty"`    // условие на тело ответа, без него - весь response
 }
 
 // Receive - описание ожидаемого сообщения из websocket соединения
 type Receive struct {
-	Channel string                      `yaml:"channel"`
-	Timeout string                      `yaml:"timeout"`
-	Filter  []validators.ValidatorDescr `yaml:"filter"`
+	Channel string                      `yaml:"channel,omitempty"`
+	Timeout string                      `yaml:"timeout,omitempty"`
+	Filter  []validators.ValidatorDescr `yaml:"filter,omitempty"`
+}
+
+// Snapshot - сравнение тела ответа и websocket сообщения с сохраненным снимком
+type Snapshot struct {
+	// Ignore - JSONPath пути к изменчивым значениям: идентификаторам, датам
+	Ignore []string `yaml:"ignore,omitempty"`
 }
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test/validators/openapi"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// validContract сверяет запрос и ответ со спецификацией OpenAPI.
+// Недокументированные операции и поля, а так же ошибки в запросе - предупреждения.
+func (r *RunnerGroup) validContract(logger zerolog.Logger, resp *http.Response, body []byte) bool {
+	exchange := openapi.Exchange{
+		Method:          resp.Request.Method,
+		Path:            resp.Request.URL.Path,
+		RequestHeaders:  resp.Request.Header,
+		Status:          resp.StatusCode,
+		ResponseHeaders: resp.Header,
+		ResponseBody:    body,
+	}
+
+	// Тело берется из самого запроса: в отчет multipart попадает без содержимого файлов
+	if resp.Request.GetBody != nil {
+		if body, err := resp.Request.GetBody(); err == nil {
+			exchange.RequestBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	operation, violations := r.spec.Check(exchange)
+	logger = logger.With().Str("validator", "openapi").Str("operation", operation).Logger()
+
+	if len(violations) == 0 {
+		return r.ruleChecked(logger, "openapi", 0, rules.Rule{Key: operation}, nil)
+	}
+
+	warning := rules.SeverityWarning
+	allValid := true
+
+	for index, violation := range violations {
+		rule := rules.Rule{Key: violation.Key}
+		if violation.Warning {
+			rule.Severity = &warning
+		}
+
+		if !r.ruleChecked(logger.With().Str("rule.key", violation.Key).Logger(), "openapi", index, rule, violation.Err) {
+			allValid = false
+		}
+	}
+
+	return allValid
+}
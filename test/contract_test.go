@@ -0,0 +1,124 @@
+package test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+const contractSpec = `
+openapi: 3.0.3
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        201:
+          description: Пользователь создан
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+`
+
+func TestContract(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("reply") {
+		case "extra":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1, "login": "ivan"}`))
+		case "status":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case "schema":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "1"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		}
+	}))
+	defer srv.Close()
+
+	specPath := filepath.Join(t.TempDir(), "openapi.yaml")
+	assert.Nil(t, ioutil.WriteFile(specPath, []byte(contractSpec), 0644))
+
+	cases := []struct {
+		Name     string
+		request  Request
+		status   Status
+		warnings []string
+		errors   []string
+	}{
+		{
+			Name:    "Запрос и ответ по спецификации",
+			request: Request{URL: srv.URL + "/users", Method: http.MethodPost, Body: `{"name": "Ivan"}`},
+			status:  StatusPassed,
+		},
+		{
+			Name:     "Недокументированное поле ответа - предупреждение",
+			request:  Request{URL: srv.URL + "/users?reply=extra", Method: http.MethodPost, Body: `{"name": "Ivan"}`},
+			status:   StatusPassed,
+			warnings: []string{"body field '/login' is not documented"},
+		},
+		{
+			Name:     "Ошибка в теле запроса - предупреждение",
+			request:  Request{URL: srv.URL + "/users", Method: http.MethodPost, Body: `{"name": 1}`},
+			status:   StatusPassed,
+			warnings: []string{"request body /name: expected type string, got integer"},
+		},
+		{
+			Name:     "Недокументированная операция - предупреждение",
+			request:  Request{URL: srv.URL + "/orders", Method: http.MethodGet},
+			status:   StatusPassed,
+			warnings: []string{"operation GET /orders is not documented"},
+		},
+		{
+			Name:    "Недокументированный код ответа роняет тест",
+			request: Request{URL: srv.URL + "/users?reply=status", Method: http.MethodPost, Body: `{"name": "Ivan"}`},
+			status:  StatusFailed,
+			errors:  []string{"status 200 is not documented for POST /users"},
+		},
+		{
+			Name:    "Тело ответа не по схеме роняет тест",
+			request: Request{URL: srv.URL + "/users?reply=schema", Method: http.MethodPost, Body: `{"name": "Ivan"}`},
+			status:  StatusFailed,
+			errors:  []string{"body /id: expected type integer, got string"},
+		},
+	}
+
+	for _, c := range cases {
+		c.request.Headers = map[string]string{"Content-Type": "application/json"}
+
+		result := NewRunner(zerolog.Nop(), Options{OpenAPI: specPath}).Run(Group{
+			Name:  "/contract",
+			Tests: []Case{{Name: c.Name, Request: c.request}},
+		})
+		if !assert.Len(t, result.Cases, 1, c.Name) {
+			continue
+		}
+
+		caseResult := result.Cases[0]
+		assert.Equal(t, c.status, caseResult.Status, c.Name)
+		assert.Equal(t, c.warnings, caseResult.Warnings, c.Name)
+		assert.Equal(t, c.errors, caseResult.Errors, c.Name)
+	}
+}
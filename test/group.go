@@ -10,5 +10,16 @@ type Group struct {
 
 // Init - описание инициализации группы тестов
 type Init struct {
-	Store map[string]string `yaml:"store"`
+	Store map[string]interface{} `yaml:"store,omitempty"`
+	// ContinueOnFailure - продолжать выполнение группы после упавшего теста
+	ContinueOnFailure *bool `yaml:"continue-on-failure,omitempty"`
+	// Setup - тесты, которые выполняются перед тестами группы
+	Setup []Case `yaml:"-"`
+	// Teardown - тесты, которые выполняются после тестов группы, даже если они упали
+	Teardown []Case `yaml:"-"`
+	// Global - группа глобальной подготовки. Выполняется раньше остальных групп,
+	// а её переменные доступны всем другим группам только для чтения.
+	Global bool `yaml:"global,omitempty"`
+	// OpenAPI - путь к спецификации OpenAPI 3, по которой проверяются все HTTP запросы группы
+	OpenAPI string `yaml:"openapi,omitempty"`
 }
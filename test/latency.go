@@ -0,0 +1,112 @@
+package test
+
+import (
+	"crypto/tls"
+	"math"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Названия замеров времени запроса.
+// Используются как ключи в правилах response.latency и в именах переменных хранилища.
+const (
+	LatencyTotal   = "total"
+	LatencyDNS     = "dns"
+	LatencyConnect = "connect"
+	LatencyTLS     = "tls"
+	LatencyTTFB    = "ttfb"
+)
+
+// timings - замеры времени выполнения запроса.
+// Обработчики httptrace могут вызываться из разных горутин, например при подключении
+// к IPv4 и IPv6 адресам одновременно, поэтому замеры защищены мьютексом.
+type timings struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	done         time.Time
+}
+
+func newTimings() *timings {
+	return &timings{
+		start: time.Now(),
+	}
+}
+
+// trace возвращает обработчики событий HTTP клиента, которые заполняют замеры
+func (t *timings) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mark(&t.dnsDone)
+		},
+		ConnectStart: func(_, _ string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			// При нескольких адресах засекаем первую попытку
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, _ error) {
+			t.mark(&t.connectDone)
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mark(&t.tlsDone)
+		},
+		GotFirstResponseByte: func() {
+			t.mark(&t.firstByte)
+		},
+	}
+}
+
+// mark записывает текущее время в замер
+func (t *timings) mark(moment *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*moment = time.Now()
+}
+
+// finish фиксирует окончание запроса
+func (t *timings) finish() {
+	t.mark(&t.done)
+}
+
+// values возвращает замеры в миллисекундах.
+// Этапы, которых не было (например, соединение взято из пула), равны нулю.
+func (t *timings) values() map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return map[string]float64{
+		LatencyTotal:   milliseconds(t.start, t.done),
+		LatencyDNS:     milliseconds(t.dnsStart, t.dnsDone),
+		LatencyConnect: milliseconds(t.connectStart, t.connectDone),
+		LatencyTLS:     milliseconds(t.tlsStart, t.tlsDone),
+		LatencyTTFB:    milliseconds(t.start, t.firstByte),
+	}
+}
+
+func milliseconds(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() {
+		return 0
+	}
+
+	ms := float64(to.Sub(from)) / float64(time.Millisecond)
+
+	return math.Round(ms*1000) / 1000
+}
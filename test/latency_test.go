@@ -0,0 +1,108 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestMilliseconds(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		Name   string
+		from   time.Time
+		to     time.Time
+		expect float64
+	}{
+		{Name: "Этапа не было", from: time.Time{}, to: start, expect: 0},
+		{Name: "Этап не закончился", from: start, to: time.Time{}, expect: 0},
+		{Name: "Целые миллисекунды", from: start, to: start.Add(250 * time.Millisecond), expect: 250},
+		{Name: "Округление до микросекунд", from: start, to: start.Add(1234567 * time.Nanosecond), expect: 1.235},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expect, milliseconds(c.from, c.to), c.Name)
+	}
+}
+
+func TestTimingsValues(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	// Соединение взято из пула: dns, connect и tls не было
+	tm := &timings{start: start, firstByte: at(30), done: at(45)}
+	assert.Equal(t, map[string]float64{
+		LatencyTotal:   45,
+		LatencyDNS:     0,
+		LatencyConnect: 0,
+		LatencyTLS:     0,
+		LatencyTTFB:    30,
+	}, tm.values())
+
+	tm = &timings{
+		start:        start,
+		dnsStart:     at(1),
+		dnsDone:      at(3),
+		connectStart: at(3),
+		connectDone:  at(8),
+		tlsStart:     at(8),
+		tlsDone:      at(20),
+		firstByte:    at(40),
+		done:         at(50),
+	}
+	assert.Equal(t, map[string]float64{
+		LatencyTotal:   50,
+		LatencyDNS:     2,
+		LatencyConnect: 5,
+		LatencyTLS:     12,
+		LatencyTTFB:    40,
+	}, tm.values())
+}
+
+func TestLatencyRules(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var40 := "40"
+	var10 := "10"
+	varWarning := "warning"
+
+	cases := []struct {
+		Name   string
+		rules  []rules.Rule
+		expect Status
+	}{
+		{Name: "Запрос дольше 40 мс", rules: []rules.Rule{{Key: LatencyTotal, Greater: &var40}}, expect: StatusPassed},
+		{Name: "Без key проверяется total", rules: []rules.Rule{{Greater: &var40}}, expect: StatusPassed},
+		{Name: "До первого байта дольше 10 мс", rules: []rules.Rule{{Key: LatencyTTFB, Less: &var10}}, expect: StatusFailed},
+		{Name: "Предупреждение не роняет тест", rules: []rules.Rule{{Key: LatencyTotal, Less: &var10, Severity: &varWarning}}, expect: StatusPassed},
+		{Name: "Неизвестный замер", rules: []rules.Rule{{Key: "queue", Less: &var10}}, expect: StatusFailed},
+	}
+
+	for _, c := range cases {
+		runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+		result := runner.Run(Case{
+			Name:     c.Name,
+			Request:  Request{URL: srv.URL, Method: http.MethodGet},
+			Response: Response{Latency: c.rules},
+		})
+
+		assert.Equal(t, c.expect, result.Status, "%s: %v", c.Name, result.Errors)
+		assert.GreaterOrEqual(t, result.Latency[LatencyTotal], 50.0, c.Name)
+
+		total, _ := runner.store.Get("latency_" + LatencyTotal)
+		assert.Equal(t, result.Latency[LatencyTotal], total, c.Name)
+	}
+}
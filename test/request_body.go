@@ -0,0 +1,210 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// requestBody - подготовленное тело запроса
+type requestBody struct {
+	data []byte
+	// contentType - тип содержимого, который нужно установить. Пустой - не менять
+	contentType string
+	// force - установить тип содержимого, даже если он задан в заголовках: без boundary тело не разобрать
+	force bool
+	// logged - тело для лога и отчета. Содержимое файлов в него не попадает
+	logged string
+}
+
+// prepareBody собирает тело запроса из body, body-file, form или multipart.
+// Указать можно только один из вариантов.
+func (r *RunnerGroup) prepareBody(req Request) (requestBody, error) {
+	sources := 0
+	for _, set := range []bool{req.Body != "", req.BodyFile != "", len(req.Form) > 0, len(req.Multipart) > 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return requestBody{}, fmt.Errorf("only one of body, body-file, form and multipart can be set")
+	}
+
+	switch {
+	case req.BodyFile != "":
+		return r.fileBody(req.BodyFile)
+	case len(req.Form) > 0:
+		return r.formBody(req.Form)
+	case len(req.Multipart) > 0:
+		return r.multipartBody(req.Multipart)
+	}
+
+	body, err := r.store.Replace(req.Body)
+	if err != nil {
+		return requestBody{}, fmt.Errorf("preparing body: %w", err)
+	}
+
+	return requestBody{data: []byte(body), logged: body}, nil
+}
+
+// fileBody читает тело из файла и заменяет в нем переменные
+func (r *RunnerGroup) fileBody(path string) (requestBody, error) {
+	path, err := r.store.Replace(path)
+	if err != nil {
+		return requestBody{}, fmt.Errorf("preparing body-file: %w", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return requestBody{}, fmt.Errorf("reading body-file: %w", err)
+	}
+
+	body, err := r.store.Replace(string(b))
+	if err != nil {
+		return requestBody{}, fmt.Errorf("preparing body-file '%s': %w", path, err)
+	}
+
+	return requestBody{data: []byte(body), logged: body}, nil
+}
+
+// formBody собирает тело application/x-www-form-urlencoded
+func (r *RunnerGroup) formBody(form map[string]string) (requestBody, error) {
+	values := make(url.Values, len(form))
+	for k, v := range form {
+		value, err := r.store.Replace(v)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("preparing form field '%s': %w", k, err)
+		}
+
+		values.Set(k, value)
+	}
+
+	body := values.Encode()
+
+	return requestBody{
+		data:        []byte(body),
+		contentType: "application/x-www-form-urlencoded",
+		logged:      body,
+	}, nil
+}
+
+// multipartBody собирает тело multipart/form-data.
+// Поля шаблонизируются, файлы отправляются как есть.
+func (r *RunnerGroup) multipartBody(parts []Part) (requestBody, error) {
+	var (
+		buf    bytes.Buffer
+		logged []string
+	)
+
+	writer := multipart.NewWriter(&buf)
+
+	for i, part := range parts {
+		prepared, err := r.preparePart(part)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("multipart[%d]: %w", i, err)
+		}
+
+		if prepared.File == "" {
+			if err = writer.WriteField(prepared.Name, prepared.Value); err != nil {
+				return requestBody{}, fmt.Errorf("multipart[%d]: %w", i, err)
+			}
+
+			logged = append(logged, fmt.Sprintf("%s: %s", prepared.Name, prepared.Value))
+			continue
+		}
+
+		content, err := ioutil.ReadFile(prepared.File)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("multipart[%d]: reading file: %w", i, err)
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     prepared.Name,
+			"filename": prepared.Filename,
+		}))
+		header.Set("Content-Type", prepared.ContentType)
+
+		w, err := writer.CreatePart(header)
+		if err == nil {
+			_, err = w.Write(content)
+		}
+		if err != nil {
+			return requestBody{}, fmt.Errorf("multipart[%d]: %w", i, err)
+		}
+
+		logged = append(logged, fmt.Sprintf("%s: @%s (%s, %d bytes)", prepared.Name, prepared.Filename, prepared.ContentType, len(content)))
+	}
+
+	if err := writer.Close(); err != nil {
+		return requestBody{}, fmt.Errorf("multipart: %w", err)
+	}
+
+	return requestBody{
+		data:        buf.Bytes(),
+		contentType: writer.FormDataContentType(),
+		force:       true,
+		logged:      strings.Join(logged, "\n"),
+	}, nil
+}
+
+// preparePart заменяет переменные в части multipart и заполняет имя и тип файла
+func (r *RunnerGroup) preparePart(part Part) (Part, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"name", &part.Name},
+		{"value", &part.Value},
+		{"file", &part.File},
+		{"filename", &part.Filename},
+		{"content-type", &part.ContentType},
+	}
+
+	for _, field := range fields {
+		value, err := r.store.Replace(*field.value)
+		if err != nil {
+			return part, fmt.Errorf("preparing %s: %w", field.name, err)
+		}
+
+		*field.value = value
+	}
+
+	if part.Name == "" {
+		return part, fmt.Errorf("empty part name")
+	}
+
+	if part.File == "" {
+		return part, nil
+	}
+
+	if part.Filename == "" {
+		part.Filename = filepath.Base(part.File)
+	}
+
+	if part.ContentType == "" {
+		part.ContentType = mime.TypeByExtension(filepath.Ext(part.File))
+	}
+	if part.ContentType == "" {
+		part.ContentType = "application/octet-stream"
+	}
+
+	return part, nil
+}
+
+// hasHeader проверяет наличие заголовка без учета регистра имени
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
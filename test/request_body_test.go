@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func bodyRunner() *RunnerGroup {
+	return NewRunnerGroup(Group{Init: Init{Store: map[string]interface{}{
+		"login": "ivan",
+		"id":    42,
+	}}}, zerolog.Nop(), nil)
+}
+
+func TestBodyFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "user.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"login": "{{.login}}"}`), 0o644))
+
+	prepared, err := bodyRunner().prepareHTTPRequest(context.Background(), Request{Method: "POST", URL: "/users", BodyFile: path})
+	assert.Nil(t, err)
+
+	data, err := ioutil.ReadAll(prepared.req.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"login": "ivan"}`, string(data))
+	assert.Equal(t, `{"login": "ivan"}`, prepared.body)
+	assert.Empty(t, prepared.req.Header.Get("Content-Type"))
+}
+
+func TestFormBody(t *testing.T) {
+	runner := bodyRunner()
+
+	prepared, err := runner.prepareHTTPRequest(context.Background(), Request{
+		Method: "POST",
+		URL:    "/login",
+		Form:   map[string]string{"login": "{{.login}}", "redirect": "/a?b=c"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded", prepared.req.Header.Get("Content-Type"))
+
+	data, err := ioutil.ReadAll(prepared.req.Body)
+	assert.Nil(t, err)
+
+	values, err := url.ParseQuery(string(data))
+	assert.Nil(t, err)
+	assert.Equal(t, url.Values{"login": {"ivan"}, "redirect": {"/a?b=c"}}, values)
+
+	// Заданный пользователем тип содержимого не меняется
+	prepared, err = runner.prepareHTTPRequest(context.Background(), Request{
+		Method:  "POST",
+		URL:     "/login",
+		Headers: map[string]string{"content-type": "application/x-www-form-urlencoded; charset=utf-8"},
+		Form:    map[string]string{"login": "{{.login}}"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded; charset=utf-8", prepared.req.Header.Get("Content-Type"))
+}
+
+func TestMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "report.json")
+	assert.Nil(t, os.WriteFile(file, []byte(`{"login": "{{.login}}"}`), 0o644))
+
+	prepared, err := bodyRunner().prepareHTTPRequest(context.Background(), Request{
+		Method: "POST",
+		URL:    "/upload",
+		// Тип без boundary заменяется, иначе сервер не разберет тело
+		Headers: map[string]string{"Content-Type": "multipart/form-data"},
+		Multipart: []Part{
+			{Name: "user_{{.id}}", Value: "{{.login}}"},
+			{Name: "report", File: file},
+			{Name: "raw", File: file, Filename: "{{.login}}.txt", ContentType: "text/plain"},
+		},
+	})
+	assert.Nil(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(prepared.req.Header.Get("Content-Type"))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/form-data", mediaType)
+	assert.NotEmpty(t, params["boundary"])
+	assert.Equal(t, prepared.req.Header.Get("Content-Type"), prepared.headers["Content-Type"])
+
+	reader := multipart.NewReader(prepared.req.Body, params["boundary"])
+
+	type part struct {
+		name, filename, contentType, content string
+	}
+
+	var parts []part
+	for {
+		p, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+
+		content, err := ioutil.ReadAll(p)
+		assert.Nil(t, err)
+		parts = append(parts, part{p.FormName(), p.FileName(), p.Header.Get("Content-Type"), string(content)})
+	}
+
+	assert.Equal(t, []part{
+		{name: "user_42", content: "ivan"},
+		// Файлы отправляются как есть, без замены переменных
+		{name: "report", filename: "report.json", contentType: "application/json", content: `{"login": "{{.login}}"}`},
+		{name: "raw", filename: "ivan.txt", contentType: "text/plain", content: `{"login": "{{.login}}"}`},
+	}, parts)
+
+	// Содержимое файлов не попадает в лог
+	assert.Equal(t, "user_42: ivan\nreport: @report.json (application/json, 23 bytes)\nraw: @ivan.txt (text/plain, 23 bytes)", prepared.body)
+}
+
+func TestBodySources(t *testing.T) {
+	runner := bodyRunner()
+
+	_, err := runner.prepareBody(Request{Body: "{}", Form: map[string]string{"a": "b"}})
+	assert.EqualError(t, err, "only one of body, body-file, form and multipart can be set")
+
+	_, err = runner.prepareBody(Request{Multipart: []Part{{Value: "x"}}})
+	assert.EqualError(t, err, "multipart[0]: empty part name")
+
+	_, err = runner.prepareBody(Request{Multipart: []Part{{Name: "file", File: "/not/exists.bin"}}})
+	assert.ErrorContains(t, err, "multipart[0]: reading file")
+}
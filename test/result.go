@@ -0,0 +1,109 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Status - итог выполнения отдельного теста
+type Status string
+
+const (
+	// StatusPassed - все проверки прошли
+	StatusPassed Status = "passed"
+	// StatusFailed - ответ не прошел проверку или запрос не удалось выполнить
+	StatusFailed Status = "failed"
+	// StatusSkipped - тест не запускался
+	StatusSkipped Status = "skipped"
+	// StatusErrored - тест описан с ошибкой и не может быть выполнен
+	StatusErrored Status = "errored"
+)
+
+// CaseResult - результат выполнения отдельного теста
+type CaseResult struct {
+	Name     string
+	Filename string
+	Status   Status
+	Duration time.Duration
+	// Errors - сообщения обо всех ошибках теста
+	Errors []string
+	// Warnings - сообщения о не прошедших правилах с уровнем warning и info
+	Warnings []string
+	// Request - отправленный запрос, если он был
+	Request *RequestDetails
+	// Response - полученный ответ, если он был
+	Response *ResponseDetails
+	// Message - полученное из websocket соединения сообщение, если оно было
+	Message *string
+	// Latency - замеры времени запроса в миллисекундах: total, dns, connect, tls, ttfb
+	Latency map[string]float64
+	// Rules - итоги проверки всех правил теста
+	Rules []RuleResult
+}
+
+// RuleResult - итог проверки отдельного правила
+type RuleResult struct {
+	// Validator - чем проверялось правило: header, code или тип валидатора тела
+	Validator string
+	// Index - порядковый номер правила в валидаторе
+	Index    int
+	Key      string
+	Expected string
+	// Actual - фактическое значение, известно только для не прошедших правил
+	Actual  string
+	Passed  bool
+	Message string
+	// Severity - уровень важности, заполняется только для не прошедших правил
+	Severity string
+}
+
+// RequestDetails - данные отправленного запроса
+type RequestDetails struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// ResponseDetails - данные полученного ответа
+type ResponseDetails struct {
+	Code    int                 `json:"code"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// GroupResult - результат выполнения группы тестов
+type GroupResult struct {
+	Name     string
+	Duration time.Duration
+	Cases    []CaseResult
+}
+
+// Count возвращает количество тестов группы с указанным статусом
+func (g GroupResult) Count(status Status) int {
+	var count int
+	for _, c := range g.Cases {
+		if c.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// formatValue приводит проверенное значение к строке для лога и отчетов
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case [][]byte:
+		return "[" + string(bytes.Join(v, []byte(","))) + "]"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test/validators"
+)
+
+// retry отправляет запрос, пока ответ не пройдет проверку или не закончатся попытки.
+// Проверка - это until, а если он не указан, то вся секция response.
+// Промежуточные попытки не пишут лог, не влияют на результат и не сохраняют переменные,
+// последняя попытка проверяется как обычный запрос вместе с условием until.
+func (r *RunnerGroup) retry(logger zerolog.Logger, test Case) bool {
+	attempts, interval, backoff, err := r.retryParams(test.Retry)
+	if err != nil {
+		return r.errored(logger, fmt.Errorf("preparing retry: %w", err))
+	}
+
+	for attempt := 1; attempt < attempts; attempt++ {
+		var resp *http.Response
+		var body []byte
+
+		passed, attemptResult := r.quiet(logger, func(logger zerolog.Logger) bool {
+			// store из правил попытки пишет в копию хранилища,
+			// переменные сохранит проверка ответа, прошедшего условие
+			groupStore := r.store
+			r.store = groupStore.Copy()
+			defer func() {
+				r.store = groupStore
+			}()
+
+			var ok bool
+			resp, ok = r.request(logger, test.Request)
+			if !ok || resp == nil {
+				return false
+			}
+
+			body, ok = readBody(resp)
+			if !ok {
+				return false
+			}
+
+			if len(test.Retry.Until) > 0 {
+				return r.validUntil(logger, test.Retry.Until, body)
+			}
+
+			return r.validateResponse(logger, resp, test.Response)
+		})
+
+		if passed {
+			logger.Debug().Int("attempt", attempt).Msg("retry condition passed")
+
+			if r.result != nil {
+				r.result.Request = attemptResult.Request
+			}
+
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return r.validateResponse(logger, resp, test.Response)
+		}
+
+		logger.Debug().
+			Int("attempt", attempt).
+			Str("interval", interval.String()).
+			Msg("retry condition not passed")
+
+		time.Sleep(interval)
+		interval = time.Duration(float64(interval) * backoff)
+	}
+
+	resp, ok := r.request(logger, test.Request)
+	if !ok || resp == nil {
+		return ok
+	}
+
+	if len(test.Retry.Until) == 0 {
+		return r.validateResponse(logger, resp, test.Response)
+	}
+
+	body, ok := readBody(resp)
+	if !ok {
+		return r.error(logger, fmt.Errorf("reading response"))
+	}
+
+	// На последней попытке условие проверяется с записью в лог и результат теста
+	passed := r.validUntil(logger, test.Retry.Until, body)
+	if !passed {
+		r.error(logger, fmt.Errorf("retry condition not met after %d attempts", attempts))
+	}
+
+	return r.validateResponse(logger, resp, test.Response) && passed
+}
+
+// validUntil проверяет условие повтора.
+// Уровень важности правил не учитывается: с severity warning или info
+// не прошедшее условие считалось бы выполненным.
+func (r *RunnerGroup) validUntil(logger zerolog.Logger, until []validators.ValidatorDescr, body []byte) bool {
+	r.ignoreSeverity = true
+	defer func() {
+		r.ignoreSeverity = false
+	}()
+
+	return r.validBody(logger, until, body)
+}
+
+// retryParams подготавливает параметры повтора:
+// количество попыток, паузу перед следующей попыткой и множитель паузы
+func (r *RunnerGroup) retryParams(retry Retry) (int, time.Duration, float64, error) {
+	strAttempts, err := r.store.Replace(retry.Attempts)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("preparing attempts: %w", err)
+	}
+
+	attempts, err := strconv.Atoi(strAttempts)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("parsing attempts: %w", err)
+	}
+
+	if attempts < 1 {
+		return 0, 0, 0, fmt.Errorf("attempts must be greater than 0")
+	}
+
+	interval, err := r.retryFloat(retry.Interval, 1)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("interval: %w", err)
+	}
+
+	backoff, err := r.retryFloat(retry.Backoff, 1)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("backoff: %w", err)
+	}
+
+	if interval < 0 || backoff <= 0 {
+		return 0, 0, 0, fmt.Errorf("interval and backoff must be positive")
+	}
+
+	return attempts, time.Duration(interval * float64(time.Second)), backoff, nil
+}
+
+// retryFloat парсит дробное число с переменными, для пустой строки возвращает def
+func (r *RunnerGroup) retryFloat(str string, def float64) (float64, error) {
+	str, err := r.store.Replace(str)
+	if err != nil {
+		return 0, fmt.Errorf("preparing: %w", err)
+	}
+
+	if str == "" {
+		return def, nil
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing float: %w", err)
+	}
+
+	return value, nil
+}
+
+// readBody вычитывает тело ответа и подменяет его копией, чтобы его можно было прочитать повторно
+func readBody(resp *http.Response) ([]byte, bool) {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, false
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, true
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// reportServer отвечает статусом 2, начиная с попытки readyAt, до этого - статусом 1
+func reportServer(readyAt int32) (*httptest.Server, *int32) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := 1
+		if atomic.AddInt32(&hits, 1) >= readyAt {
+			status = 2
+		}
+
+		fmt.Fprintf(w, `{"status": %d}`, status)
+	}))
+
+	return srv, &hits
+}
+
+func retryCase(url, attempts string) Case {
+	status := "2"
+	code := "200"
+
+	return Case{
+		Name:    "Ожидание отчета",
+		Request: Request{URL: url, Method: http.MethodGet},
+		Retry: Retry{
+			Attempts: attempts,
+			Interval: "0",
+			Until: []validators.ValidatorDescr{{
+				Type:  "json",
+				Rules: []rules.Rule{{Key: "status", Type: rules.TypeInteger, Equal: &status}},
+			}},
+		},
+		Response: Response{Code: []rules.Rule{{Equal: &code}}},
+	}
+}
+
+func TestRetryUntilMet(t *testing.T) {
+	srv, hits := reportServer(3)
+	defer srv.Close()
+
+	runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+	result := runner.Run(retryCase(srv.URL, "5"))
+
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestRetryUntilMetOnLastAttempt(t *testing.T) {
+	srv, hits := reportServer(3)
+	defer srv.Close()
+
+	runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+	result := runner.Run(retryCase(srv.URL, "3"))
+
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestRetryUntilNotMet(t *testing.T) {
+	srv, hits := reportServer(100)
+	defer srv.Close()
+
+	runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+	result := runner.Run(retryCase(srv.URL, "3"))
+
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Contains(t, result.Errors, "retry condition not met after 3 attempts")
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+}
+
+func TestRetryUntilIgnoresSeverity(t *testing.T) {
+	srv, hits := reportServer(3)
+	defer srv.Close()
+
+	// Условие с уровнем warning все равно ждет нужного ответа
+	warning := rules.SeverityWarning
+	test := retryCase(srv.URL, "5")
+	test.Retry.Until[0].Rules[0].Severity = &warning
+
+	runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+	result := runner.Run(test)
+
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+	assert.Equal(t, int32(3), atomic.LoadInt32(hits))
+
+	srv, _ = reportServer(100)
+	defer srv.Close()
+
+	test = retryCase(srv.URL, "2")
+	test.Retry.Until[0].Rules[0].Severity = &warning
+	result = runner.Run(test)
+
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Contains(t, result.Errors, "retry condition not met after 2 attempts")
+}
+
+func TestRetryQuietAttemptsDoNotStore(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&hits, 1) < 3 {
+			fmt.Fprint(w, `{"status": 1, "error": "not ready"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"status": 2}`)
+	}))
+	defer srv.Close()
+
+	status := "2"
+	storeError := "error"
+	varFalse := false
+
+	runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+	result := runner.Run(Case{
+		Name:    "Ожидание отчета",
+		Request: Request{URL: srv.URL, Method: http.MethodGet},
+		Retry:   Retry{Attempts: "5", Interval: "0"},
+		Response: Response{Body: []validators.ValidatorDescr{{
+			Type: "json",
+			Rules: []rules.Rule{
+				{Key: "status", Type: rules.TypeInteger, Equal: &status},
+				{Key: "error", Required: &varFalse, Store: &storeError},
+			},
+		}}},
+	})
+
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+
+	// Переменная из неудачной попытки не сохранилась
+	_, ok := runner.store.Get("error")
+	assert.False(t, ok)
+}
+
+func TestRetryParams(t *testing.T) {
+	runner := NewRunnerGroup(Group{Init: Init{Store: map[string]interface{}{"attempts": 4}}}, zerolog.Nop(), nil)
+
+	cases := []struct {
+		retry     Retry
+		attempts  int
+		interval  time.Duration
+		backoff   float64
+		expectErr string
+	}{
+		{retry: Retry{Attempts: "3"}, attempts: 3, interval: time.Second, backoff: 1},
+		{retry: Retry{Attempts: "{{.attempts}}", Interval: "0.5", Backoff: "2"}, attempts: 4, interval: 500 * time.Millisecond, backoff: 2},
+		{retry: Retry{Attempts: "1", Interval: "0"}, attempts: 1, interval: 0, backoff: 1},
+		{retry: Retry{Attempts: "0"}, expectErr: "attempts must be greater than 0"},
+		{retry: Retry{Attempts: "many"}, expectErr: `parsing attempts: strconv.Atoi: parsing "many": invalid syntax`},
+		{retry: Retry{Attempts: "3", Interval: "-1"}, expectErr: "interval and backoff must be positive"},
+		{retry: Retry{Attempts: "3", Backoff: "0"}, expectErr: "interval and backoff must be positive"},
+		{retry: Retry{Attempts: "3", Backoff: "twice"}, expectErr: `backoff: parsing float: strconv.ParseFloat: parsing "twice": invalid syntax`},
+	}
+
+	for _, c := range cases {
+		attempts, interval, backoff, err := runner.retryParams(c.retry)
+		if c.expectErr != "" {
+			assert.EqualError(t, err, c.expectErr)
+			continue
+		}
+
+		assert.Nil(t, err)
+		assert.Equal(t, c.attempts, attempts)
+		assert.Equal(t, c.interval, interval)
+		assert.Equal(t, c.backoff, backoff)
+	}
+}
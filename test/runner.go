@@ -1,34 +1,164 @@
 package test
 
 import (
-	"github.com/rs/zerolog/log"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test/validators/openapi"
 )
 
+// Options - параметры запуска тестов
+type Options struct {
+	// ContinueOnFailure - продолжать выполнение группы после упавшего теста.
+	// Может быть переопределено в init файле группы.
+	ContinueOnFailure bool
+	// Globals - переменные глобальных групп, доступны всем группам только для чтения
+	Globals map[string]interface{}
+	// GlobalsFailed - в глобальных группах были упавшие тесты,
+	// поэтому части глобальных переменных может не быть
+	GlobalsFailed bool
+	// OpenAPI - путь к спецификации OpenAPI 3 для проверки контракта.
+	// Может быть переопределено в init файле группы.
+	OpenAPI string
+	// UpdateSnapshots - перезаписать снимки ответов вместо сравнения с ними
+	UpdateSnapshots bool
+}
+
 // Runner - средство запуска всех тестов
 type Runner struct {
-	success int
-	errors  int
+	logger  zerolog.Logger
+	options Options
+}
+
+// NewRunner создает средство запуска тестов.
+// Весь лог групп пишется в переданный логгер.
+func NewRunner(logger zerolog.Logger, options Options) *Runner {
+	return &Runner{
+		logger:  logger,
+		options: options,
+	}
 }
 
-func NewRunner() *Runner {
-	return &Runner{}
+// Run запускает выполнение группы тестов.
+// Вначале выполняются setup тесты из init файла, затем тесты группы, в конце - teardown тесты.
+// По-умолчанию после первого упавшего теста остальные тесты группы не запускаются
+// и попадают в результат со статусом StatusSkipped. Teardown тесты выполняются всегда.
+func (r *Runner) Run(group Group) GroupResult {
+	result, _ := r.run(group)
+	return result
 }
 
-// Run запускает выполнение группы тестов
-func (r *Runner) Run(group Group) (errors int, success int) {
-	log.Trace().Str("group", group.Name).Msg("====== RUN GROUP ======")
+// RunGlobal запускает группу глобальной подготовки.
+// Вторым значением возвращает переменные, сохраненные тестами группы,
+// их нужно передать остальным группам через Options.Globals.
+func (r *Runner) RunGlobal(group Group) (GroupResult, map[string]interface{}) {
+	result, groupRunner := r.run(group)
+	return result, groupRunner.store.Saved()
+}
+
+func (r *Runner) run(group Group) (GroupResult, *RunnerGroup) {
+	r.logger.Trace().Str("group", group.Name).Msg("====== RUN GROUP ======")
+
+	start := time.Now()
+	result := GroupResult{
+		Name:  group.Name,
+		Cases: make([]CaseResult, 0, len(group.Init.Setup)+len(group.Tests)+len(group.Init.Teardown)),
+	}
+
+	groupRunner := NewRunnerGroup(group, r.logger, r.options.Globals)
+	groupRunner.updateSnapshots = r.options.UpdateSnapshots
+	groupRunner.failedBefore = r.options.GlobalsFailed
+
+	specPath := r.options.OpenAPI
+	if group.Init.OpenAPI != "" {
+		specPath = group.Init.OpenAPI
+	}
+
+	if specPath != "" {
+		spec, err := openapi.Load(specPath)
+		if err != nil {
+			r.logger.Error().Str("group", group.Name).Err(err).Msg("loading openapi spec")
+
+			for _, tests := range [][]Case{group.Init.Setup, group.Tests, group.Init.Teardown} {
+				for _, test := range tests {
+					result.Cases = append(result.Cases, errored(test, err))
+				}
+			}
+			result.Duration = time.Since(start)
+
+			return result, groupRunner
+		}
+
+		groupRunner.spec = spec
+	}
+
+	continueOnFailure := r.options.ContinueOnFailure
+	if group.Init.ContinueOnFailure != nil {
+		continueOnFailure = *group.Init.ContinueOnFailure
+	}
+
+	// Без успешного setup запускать тесты группы нет смысла
+	var stopReason string
+	for _, test := range group.Init.Setup {
+		if stopReason != "" {
+			result.Cases = append(result.Cases, skipped(test, stopReason))
+			continue
+		}
 
-	groupRunner := NewRunnerGroup(group)
-	defer groupRunner.Flush()
+		caseResult := groupRunner.Run(test)
+		result.Cases = append(result.Cases, caseResult)
+
+		if caseResult.Status != StatusPassed {
+			stopReason = "setup failed"
+		}
+	}
 
 	for _, test := range group.Tests {
-		if groupRunner.Run(test) {
-			r.success++
-		} else {
-			r.errors++
-			break
+		if stopReason != "" {
+			result.Cases = append(result.Cases, skipped(test, stopReason))
+			continue
+		}
+
+		caseResult := groupRunner.Run(test)
+		result.Cases = append(result.Cases, caseResult)
+
+		failed := caseResult.Status == StatusFailed || caseResult.Status == StatusErrored
+		if failed && !continueOnFailure {
+			stopReason = "previous test failed"
 		}
 	}
 
-	return r.errors, r.success
+	result.Cases = append(result.Cases, groupRunner.Flush()...)
+	result.Duration = time.Since(start)
+
+	r.logger.Info().
+		Str("group", group.Name).
+		Int("passed", result.Count(StatusPassed)).
+		Int("failed", result.Count(StatusFailed)).
+		Int("skipped", result.Count(StatusSkipped)).
+		Int("errored", result.Count(StatusErrored)).
+		Msg("group finished")
+
+	return result, groupRunner
+}
+
+// errored возвращает результат теста, который не удалось запустить из-за ошибки конфигурации
+func errored(test Case, err error) CaseResult {
+	return CaseResult{
+		Name:     test.Name,
+		Filename: test.Filename,
+		Status:   StatusErrored,
+		Errors:   []string{err.Error()},
+	}
+}
+
+// skipped возвращает результат не запускавшегося теста
+func skipped(test Case, reason string) CaseResult {
+	return CaseResult{
+		Name:     test.Name,
+		Filename: test.Filename,
+		Status:   StatusSkipped,
+		Errors:   []string{reason},
+	}
 }
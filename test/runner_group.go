@@ -3,56 +3,113 @@ package test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 
 	"github.com/MashinaMashina/api-tests/store"
 	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/openapi"
 	"github.com/MashinaMashina/api-tests/test/validators/rules"
 )
 
 // RunnerGroup - средство для запуска отдельных тестов в группе
 type RunnerGroup struct {
 	group         Group
+	logger        zerolog.Logger
 	store         *store.Store
 	wsConnections map[string]*wsConnect
+	// result - результат выполняемого в данный момент теста
+	result *CaseResult
+	// timings - замеры времени запроса выполняемого в данный момент теста
+	timings *timings
+	// spec - спецификация OpenAPI, по которой проверяются HTTP запросы группы
+	spec *openapi.Spec
+	// updateSnapshots - перезаписывать снимки ответов вместо сравнения
+	updateSnapshots bool
+	// failedBefore - ранее в группе (или в глобальных группах) был упавший тест.
+	// Только тогда отсутствующие переменные считаются его следствием, а не ошибкой описания.
+	failedBefore bool
+	// ignoreSeverity - любое не прошедшее правило считается ошибкой, например в условии until
+	ignoreSeverity bool
 }
 
-func NewRunnerGroup(group Group) *RunnerGroup {
+// NewRunnerGroup создает средство запуска тестов группы.
+// globals - глобальные переменные, доступные группе только для чтения.
+func NewRunnerGroup(group Group, logger zerolog.Logger, globals map[string]interface{}) *RunnerGroup {
+	s := store.NewStore(group.Init.Store)
+	s.SetReadOnly(globals)
+
 	return &RunnerGroup{
 		group:         group,
-		store:         store.NewStore(group.Init.Store),
+		logger:        logger,
+		store:         s,
 		wsConnections: make(map[string]*wsConnect),
 	}
 }
 
 // Run - Запускает выполнение отдельного теста
-func (r *RunnerGroup) Run(test Case) bool {
+func (r *RunnerGroup) Run(test Case) CaseResult {
 	// Логгер с данными запроса
-	logger := log.With().
+	logger := r.logger.With().
 		Str("test_name", test.Name).
 		Str("group", r.group.Name).
 		Str("file", test.Filename).
 		Logger()
 
+	start := time.Now()
+	r.timings = nil
+	r.result = &CaseResult{
+		Name:     test.Name,
+		Filename: test.Filename,
+		Status:   StatusPassed,
+	}
+
+	r.run(logger, test)
+
+	// Ответ упавшего теста не должен стать эталоном
+	if test.Snapshot != nil && r.result.Status == StatusPassed {
+		r.validSnapshot(logger, test)
+	}
+
+	r.result.Duration = time.Since(start)
+
+	if r.result.Status == StatusFailed || r.result.Status == StatusErrored {
+		r.failedBefore = true
+	}
+
+	return *r.result
+}
+
+// run выполняет шаги теста: ожидание сообщения, запрос и проверку ответа
+func (r *RunnerGroup) run(logger zerolog.Logger, test Case) bool {
 	if len(test.Receive.Filter) > 0 {
 		msg, ok := r.receive(logger, test.Receive)
 		if !ok {
 			return false
 		}
 
+		message := string(msg)
+		r.result.Message = &message
+
 		// Валидация Body
 		if !r.validBody(logger, test.Message, msg) {
 			return false
 		}
 	}
 
+	// Если задан повтор, запрос отправляется до успешной проверки ответа
+	if test.Request.URL != "" && test.Retry.Attempts != "" {
+		return r.retry(logger, test)
+	}
+
 	// Если есть сетевой запрос, отравляем его и проверяем ответ
 	if test.Request.URL != "" {
 		resp, ok := r.request(logger, test.Request)
@@ -79,6 +136,14 @@ func (r *RunnerGroup) validateResponse(logger zerolog.Logger, resp *http.Respons
 		return r.error(logger, fmt.Errorf("reading response: %w", err))
 	}
 
+	if r.result != nil {
+		r.result.Response = &ResponseDetails{
+			Code:    resp.StatusCode,
+			Headers: resp.Header,
+			Body:    string(body),
+		}
+	}
+
 	logger.Trace().
 		Str("response", string(body)).
 		Int("code", resp.StatusCode).
@@ -87,6 +152,16 @@ func (r *RunnerGroup) validateResponse(logger zerolog.Logger, resp *http.Respons
 
 	allValid := true
 
+	// Валидация Latency
+	if r.timings != nil {
+		latency := r.timings.values()
+		r.storeLatency(latency)
+
+		if valid := r.validLatency(logger, expectResponse.Latency, latency); !valid {
+			allValid = false
+		}
+	}
+
 	// Валидация Headers
 	if valid := r.validHeaders(logger, expectResponse.Headers, resp.Header); !valid {
 		allValid = false
@@ -101,6 +176,13 @@ func (r *RunnerGroup) validateResponse(logger zerolog.Logger, resp *http.Respons
 		allValid = false
 	}
 
+	// Проверка контракта OpenAPI, только для HTTP запросов
+	if r.spec != nil && resp.Request != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+		if valid := r.validContract(logger, resp, body); !valid {
+			allValid = false
+		}
+	}
+
 	if allValid {
 		return true
 	}
@@ -110,6 +192,8 @@ func (r *RunnerGroup) validateResponse(logger zerolog.Logger, resp *http.Respons
 
 // validHeaders проверяет заголовки
 func (r *RunnerGroup) validHeaders(logger zerolog.Logger, headerRules []rules.Rule, headers http.Header) bool {
+	allValid := true
+
 	for index, rule := range headerRules {
 		headerLogger := logger.With().
 			Str("validator", fmt.Sprintf("HeaderValidator[%d]", index)).Logger()
@@ -118,18 +202,23 @@ func (r *RunnerGroup) validHeaders(logger zerolog.Logger, headerRules []rules.Ru
 
 		rule.Type = "string"
 
-		if err, _ := validator.ValidHeader(rule, headers); err != nil {
-			return r.error(headerLogger, fmt.Errorf("headers validate: %w", err))
-		} else {
-			headerLogger.Info().Msg("rule passed")
+		err, _ := validator.ValidHeader(rule, headers)
+		if err != nil {
+			err = fmt.Errorf("headers validate: %w", err)
+		}
+
+		if !r.ruleChecked(headerLogger, "header", index, rule, err) {
+			allValid = false
 		}
 	}
 
-	return true
+	return allValid
 }
 
 // validHTTPCode проверяет HTTP код
 func (r *RunnerGroup) validHTTPCode(logger zerolog.Logger, codeRules []rules.Rule, status int) bool {
+	allValid := true
+
 	for index, rule := range codeRules {
 		httpCodeLogger := logger.With().
 			Str("validator", fmt.Sprintf("HTTPCodeValidator[%d]", index)).Logger()
@@ -138,41 +227,152 @@ func (r *RunnerGroup) validHTTPCode(logger zerolog.Logger, codeRules []rules.Rul
 
 		rule.Type = rules.TypeInteger
 
-		if err := validator.ValidHTTPCode(rule, status); err != nil {
-			return r.error(httpCodeLogger, fmt.Errorf("http code validate: %w", err))
-		} else {
-			httpCodeLogger.Info().Msg("rule passed")
+		err := validator.ValidHTTPCode(rule, status)
+		if err != nil {
+			err = fmt.Errorf("http code validate: %w", err)
+		}
+
+		if !r.ruleChecked(httpCodeLogger, "code", index, rule, err) {
+			allValid = false
 		}
 	}
 
-	return true
+	return allValid
+}
+
+// storeLatency сохраняет замеры времени запроса в хранилище и в результат теста.
+// Переменные называются latency_total, latency_dns и т.д.
+func (r *RunnerGroup) storeLatency(latency map[string]float64) {
+	for key, value := range latency {
+		r.store.SetService("latency_"+key, value)
+	}
+
+	if r.result != nil {
+		r.result.Latency = latency
+	}
+}
+
+// validLatency проверяет время выполнения запроса
+func (r *RunnerGroup) validLatency(logger zerolog.Logger, latencyRules []rules.Rule, latency map[string]float64) bool {
+	allValid := true
+
+	for index, rule := range latencyRules {
+		latencyLogger := logger.With().
+			Str("validator", fmt.Sprintf("LatencyValidator[%d]", index)).Logger()
+
+		validator := validators.NewLatencyValidator(r.store)
+
+		rule.Type = rules.TypeFloat
+
+		err := validator.ValidLatency(rule, latency)
+		if err != nil {
+			err = fmt.Errorf("latency validate: %w", err)
+		}
+
+		if !r.ruleChecked(latencyLogger, "latency", index, rule, err) {
+			allValid = false
+		}
+	}
+
+	return allValid
 }
 
 // validBody проверяет тело ответа
 func (r *RunnerGroup) validBody(logger zerolog.Logger, descriptions []validators.ValidatorDescr, body []byte) bool {
+	allValid := true
+
 	for _, validatorDescr := range descriptions {
 		validator, err := validators.NewBodyValidator(r.store, validatorDescr)
 		if err != nil {
-			return r.error(logger, fmt.Errorf("creating response validator: %w", err))
+			allValid = r.errored(logger, fmt.Errorf("creating response validator: %w", err))
+			continue
 		}
 
-		for index, rule := range validatorDescr.Rules {
+		for index, rule := range validatorDescr.ValidationRules() {
 			ruleLogger := logger.With().
 				Str("validator", fmt.Sprintf("%s[%d]", validatorDescr.Type, index)).
 				Str("rule.type", string(rule.Type)).
 				Str("rule.key", rule.Key).Logger()
 
-			if err = validator.ValidBody(rule, body); err != nil {
-				return r.error(ruleLogger, fmt.Errorf("response validate: %w", err))
-			} else {
-				ruleLogger.Info().Msg("rule passed")
+			err = validator.ValidBody(rule, body)
+			if err != nil {
+				err = fmt.Errorf("response validate: %w", err)
+			}
+
+			if !r.ruleChecked(ruleLogger, validatorDescr.Type, index, rule, err) {
+				allValid = false
 			}
 		}
 	}
 
+	return allValid
+}
+
+// ruleChecked записывает итог проверки правила в лог и в результат теста
+func (r *RunnerGroup) ruleChecked(logger zerolog.Logger, validator string, index int, rule rules.Rule, err error) bool {
+	result := RuleResult{
+		Validator: validator,
+		Index:     index,
+		Key:       rule.Key,
+		Expected:  rule.Expected(),
+		Passed:    err == nil,
+	}
+
+	if err == nil {
+		logger.Info().Msg("rule passed")
+		r.addRuleResult(result)
+
+		return true
+	}
+
+	var ruleErr *rules.Error
+	if errors.As(err, &ruleErr) {
+		result.Expected = ruleErr.Expected
+		result.Actual = formatValue(ruleErr.Actual)
+		logger = logger.With().Str("actual", result.Actual).Logger()
+	}
+	result.Message = err.Error()
+	result.Severity = r.severity(rule)
+	r.addRuleResult(result)
+
+	// Не прошедшее правило с уровнем warning или info не роняет тест
+	switch result.Severity {
+	case rules.SeverityWarning:
+		logger.Warn().Err(err).Msg("rule warning")
+	case rules.SeverityInfo:
+		logger.Info().Err(err).Msg("rule info")
+	default:
+		return r.error(logger, err)
+	}
+
+	if r.result != nil {
+		r.result.Warnings = append(r.result.Warnings, err.Error())
+	}
+
 	return true
 }
 
+// severity возвращает уровень важности правила.
+// Уровень задается у правила верхнего уровня и действует на все вложенные правила.
+func (r *RunnerGroup) severity(rule rules.Rule) string {
+	if rule.Severity == nil || r.ignoreSeverity {
+		return rules.SeverityError
+	}
+
+	severity, err := r.store.Replace(*rule.Severity)
+	if err != nil {
+		return rules.SeverityError
+	}
+
+	return rules.ParseSeverity(severity)
+}
+
+func (r *RunnerGroup) addRuleResult(result RuleResult) {
+	if r.result != nil {
+		r.result.Rules = append(r.result.Rules, result)
+	}
+}
+
 // request отправляет запрос
 func (r *RunnerGroup) request(logger zerolog.Logger, req Request) (*http.Response, bool) {
 	switch req.Protocol {
@@ -188,7 +388,7 @@ func (r *RunnerGroup) httpRequest(logger zerolog.Logger, req Request) (*http.Res
 	timeout, err := r.timeout(req.Timeout)
 
 	if err != nil {
-		return nil, r.error(logger, fmt.Errorf("preparing timeout: %w", err))
+		return nil, r.errored(logger, fmt.Errorf("preparing timeout: %w", err))
 	}
 
 	// Создаем контекст для запроса
@@ -198,7 +398,16 @@ func (r *RunnerGroup) httpRequest(logger zerolog.Logger, req Request) (*http.Res
 	request, err := r.prepareHTTPRequest(ctx, req)
 
 	if err != nil {
-		return nil, r.error(logger, fmt.Errorf("creating HTTP request: %w", err))
+		return nil, r.errored(logger, fmt.Errorf("creating HTTP request: %w", err))
+	}
+
+	if r.result != nil {
+		r.result.Request = &RequestDetails{
+			Method:  request.method,
+			URL:     request.url,
+			Headers: request.headers,
+			Body:    request.body,
+		}
 	}
 
 	logger = logger.With().
@@ -217,13 +426,27 @@ func (r *RunnerGroup) httpRequest(logger zerolog.Logger, req Request) (*http.Res
 	}
 
 	// выполняем HTTP запрос
-	resp, err := client.Do(request.req)
+	timings := newTimings()
+	resp, err := client.Do(request.req.WithContext(httptrace.WithClientTrace(ctx, timings.trace())))
 	if err != nil {
 		return nil, r.error(logger, fmt.Errorf("sending HTTP request: %w", err))
-	} else {
-		logger.Trace().Msg("success HTTP request")
 	}
 
+	// Тело вычитываем сразу: после выхода из функции контекст запроса будет отменен,
+	// а время полного получения ответа входит в замер total
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, r.error(logger, fmt.Errorf("reading response: %w", err))
+	}
+
+	timings.finish()
+	r.timings = timings
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	logger.Trace().Msg("success HTTP request")
+
 	return resp, true
 }
 
@@ -249,13 +472,13 @@ func (r *RunnerGroup) prepareHTTPRequest(ctx context.Context, req Request) (prep
 		return preparedRequest{}, fmt.Errorf("preparing url: %w", err)
 	}
 
-	body, err := r.store.Replace(req.Body)
+	body, err := r.prepareBody(req)
 
 	if err != nil {
-		return preparedRequest{}, fmt.Errorf("preparing body: %w", err)
+		return preparedRequest{}, err
 	}
 
-	reader := bytes.NewReader([]byte(body))
+	reader := bytes.NewReader(body.data)
 	request, err := http.NewRequestWithContext(ctx, method, url, reader)
 
 	if err != nil {
@@ -278,11 +501,22 @@ func (r *RunnerGroup) prepareHTTPRequest(ctx context.Context, req Request) (prep
 		request.Header.Set(parsedKey, parsedValue)
 	}
 
+	if body.contentType != "" && (body.force || !hasHeader(headers, "Content-Type")) {
+		for k := range headers {
+			if strings.EqualFold(k, "Content-Type") {
+				delete(headers, k)
+			}
+		}
+
+		headers["Content-Type"] = body.contentType
+		request.Header.Set("Content-Type", body.contentType)
+	}
+
 	return preparedRequest{
 		req:     request,
 		method:  method,
 		url:     url,
-		body:    body,
+		body:    body.logged,
 		headers: headers,
 	}, nil
 }
@@ -317,14 +551,93 @@ func (r *RunnerGroup) timeout(strTimeout string) (time.Duration, error) {
 }
 
 // Flush очищает занятые ресурсы:
-// 1. Закрывает websocket соединения
-func (r *RunnerGroup) Flush() {
+// 1. Выполняет teardown тесты из init файла, даже если тесты группы упали
+// 2. Закрывает websocket соединения
+// Возвращает результаты teardown тестов.
+func (r *RunnerGroup) Flush() []CaseResult {
+	results := make([]CaseResult, 0, len(r.group.Init.Teardown))
+	for _, test := range r.group.Init.Teardown {
+		results = append(results, r.Run(test))
+	}
+
 	for _, connect := range r.wsConnections {
-		connect.cancel()
+		connect.close()
 	}
+
+	return results
+}
+
+// quiet выполняет проверку так, чтобы она не писала лог и не влияла на результат теста.
+// Вторым значением возвращает результат, собранный во время проверки.
+func (r *RunnerGroup) quiet(logger zerolog.Logger, check func(logger zerolog.Logger) bool) (bool, CaseResult) {
+	result := r.result
+	r.result = &CaseResult{}
+	defer func() {
+		r.result = result
+	}()
+
+	// Отключенный логгер, чтобы проверка не писала лог
+	fakeLogger := logger.With().Logger().Level(zerolog.Disabled)
+
+	return check(fakeLogger), *r.result
 }
 
+// error пишет ошибку в лог и отмечает тест как не прошедший.
+// Если ошибка из-за отсутствующих в хранилище переменных, а ранее упал другой тест,
+// текущий тест отмечается как пропущенный: значит не выполнился тест, от которого он зависит.
+// Без упавших тестов переменную никто не должен был сохранить - это ошибка описания теста.
 func (r *RunnerGroup) error(logger zerolog.Logger, err error) bool {
+	var missing *store.MissingError
+	if errors.As(err, &missing) {
+		if !r.failedBefore {
+			logger.Error().Err(err).Send()
+			r.setStatus(StatusErrored, err)
+
+			return false
+		}
+
+		err = fmt.Errorf("skipped (missing dependency): %w", err)
+		logger.Warn().Err(err).Send()
+		r.setStatus(StatusSkipped, err)
+
+		return false
+	}
+
 	logger.Error().Err(err).Send()
+	r.setStatus(StatusFailed, err)
+
+	return false
+}
+
+// errored пишет ошибку в лог и отмечает тест как описанный с ошибкой
+func (r *RunnerGroup) errored(logger zerolog.Logger, err error) bool {
+	var missing *store.MissingError
+	if errors.As(err, &missing) {
+		return r.error(logger, err)
+	}
+
+	logger.Error().Err(err).Send()
+	r.setStatus(StatusErrored, err)
+
 	return false
 }
+
+// statusPriority - какой статус важнее, если в тесте было несколько ошибок
+var statusPriority = map[Status]int{
+	StatusPassed:  0,
+	StatusSkipped: 1,
+	StatusFailed:  2,
+	StatusErrored: 3,
+}
+
+// setStatus сохраняет ошибку и меняет статус теста, если новый статус важнее текущего
+func (r *RunnerGroup) setStatus(status Status, err error) {
+	if r.result == nil {
+		return
+	}
+
+	if statusPriority[status] > statusPriority[r.result.Status] {
+		r.result.Status = status
+	}
+	r.result.Errors = append(r.result.Errors, err.Error())
+}
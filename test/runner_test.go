@@ -0,0 +1,198 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test/validators"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestGlobalVars(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"token": "abc"}`))
+	}))
+	defer srv.Close()
+
+	storeToken := "token"
+	global := Group{
+		Name: "global",
+		Init: Init{Global: true, Store: map[string]interface{}{"host": "global-host"}},
+		Tests: []Case{{
+			Name:    "Авторизация",
+			Request: Request{URL: srv.URL, Method: http.MethodGet},
+			Response: Response{Body: []validators.ValidatorDescr{{
+				Type:  "json",
+				Rules: []rules.Rule{{Key: "token", Store: &storeToken}},
+			}}},
+		}},
+	}
+
+	result, vars := NewRunner(zerolog.Nop(), Options{}).RunGlobal(global)
+	assert.Equal(t, 1, result.Count(StatusPassed))
+
+	// Передаются только переменные, сохраненные тестами
+	assert.Equal(t, map[string]interface{}{"token": "abc"}, vars)
+
+	token := "{{.token}}"
+	child := NewRunnerGroup(Group{
+		Name: "reports",
+		Init: Init{Store: map[string]interface{}{"host": "child-host"}},
+	}, zerolog.Nop(), vars)
+
+	caseResult := child.Run(Case{
+		Name:    "Запрос с глобальным токеном",
+		Request: Request{URL: srv.URL, Method: http.MethodGet},
+		Response: Response{Body: []validators.ValidatorDescr{{
+			Type:  "json",
+			Rules: []rules.Rule{{Key: "token", Equal: &token}},
+		}}},
+	})
+	assert.Equal(t, StatusPassed, caseResult.Status, caseResult.Errors)
+
+	host, _ := child.store.Get("host")
+	assert.Equal(t, "child-host", host)
+
+	latency, ok := child.store.Get("latency_total")
+	assert.True(t, ok)
+	assert.Equal(t, caseResult.Latency["total"], latency)
+
+	assert.EqualError(t, child.store.Set("token", "other"), "variable 'token' is read-only")
+}
+
+func TestMissingVariables(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	code := "200"
+	storeID := "id"
+	continueOnFailure := true
+
+	create := Case{
+		Name:    "Создание",
+		Request: Request{URL: srv.URL, Method: http.MethodPost},
+		Response: Response{
+			Code: []rules.Rule{{Equal: &code}},
+			Body: []validators.ValidatorDescr{{Type: "json", Rules: []rules.Rule{{Key: "id", Store: &storeID}}}},
+		},
+	}
+	get := Case{
+		Name:    "Получение",
+		Request: Request{URL: srv.URL + "/{{.id}}", Method: http.MethodGet},
+	}
+
+	runner := NewRunner(zerolog.Nop(), Options{})
+
+	// Переменную никто не сохранял - ошибка описания теста
+	result := runner.Run(Group{Name: "typo", Tests: []Case{get}})
+	if assert.Len(t, result.Cases, 1) {
+		assert.Equal(t, StatusErrored, result.Cases[0].Status)
+		assert.Equal(t, []string{"creating HTTP request: preparing url: missing variables: id"}, result.Cases[0].Errors)
+	}
+
+	// Переменную должен был сохранить упавший тест - зависимый тест пропускается
+	result = runner.Run(Group{
+		Name:  "dependency",
+		Init:  Init{ContinueOnFailure: &continueOnFailure},
+		Tests: []Case{create, get},
+	})
+	if assert.Len(t, result.Cases, 2) {
+		assert.Equal(t, StatusFailed, result.Cases[0].Status)
+		assert.Equal(t, StatusSkipped, result.Cases[1].Status)
+	}
+
+	// Упавший тест в глобальной группе тоже объясняет отсутствие переменной
+	result = NewRunner(zerolog.Nop(), Options{GlobalsFailed: true}).Run(Group{Name: "after-global", Tests: []Case{get}})
+	if assert.Len(t, result.Cases, 1) {
+		assert.Equal(t, StatusSkipped, result.Cases[0].Status)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"status": 1}`))
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		Name     string
+		severity string
+		status   Status
+		warnings []string
+		errors   []string
+	}{
+		{
+			Name:     "Предупреждение не роняет тест",
+			severity: rules.SeverityWarning,
+			status:   StatusPassed,
+			warnings: []string{"response validate: field 'status': must be '2'"},
+		},
+		{
+			Name:     "Информация не роняет тест",
+			severity: rules.SeverityInfo,
+			status:   StatusPassed,
+			warnings: []string{"response validate: field 'status': must be '2'"},
+		},
+		{
+			Name:     "Ошибка роняет тест",
+			severity: rules.SeverityError,
+			status:   StatusFailed,
+			errors:   []string{"response validate: field 'status': must be '2'"},
+		},
+		{
+			Name:   "По-умолчанию ошибка",
+			status: StatusFailed,
+			errors: []string{"response validate: field 'status': must be '2'"},
+		},
+	}
+
+	for _, c := range cases {
+		status := "2"
+		rule := rules.Rule{Key: "status", Type: rules.TypeInteger, Equal: &status}
+		if c.severity != "" {
+			severity := c.severity
+			rule.Severity = &severity
+		}
+
+		check := Case{
+			Name:    "Проверка статуса",
+			Request: Request{URL: srv.URL, Method: http.MethodGet},
+			Response: Response{Body: []validators.ValidatorDescr{{
+				Type:  "json",
+				Rules: []rules.Rule{rule},
+			}}},
+		}
+		next := Case{
+			Name:    "Следующий тест",
+			Request: Request{URL: srv.URL, Method: http.MethodGet},
+		}
+
+		result := NewRunner(zerolog.Nop(), Options{}).Run(Group{Name: "/severity", Tests: []Case{check, next}})
+		if !assert.Len(t, result.Cases, 2, c.Name) {
+			continue
+		}
+
+		caseResult := result.Cases[0]
+		assert.Equal(t, c.status, caseResult.Status, c.Name)
+		assert.Equal(t, c.warnings, caseResult.Warnings, c.Name)
+		assert.Equal(t, c.errors, caseResult.Errors, c.Name)
+
+		if assert.Len(t, caseResult.Rules, 1, c.Name) {
+			assert.False(t, caseResult.Rules[0].Passed, c.Name)
+			assert.Equal(t, rules.ParseSeverity(c.severity), caseResult.Rules[0].Severity, c.Name)
+		}
+
+		// Предупреждение не останавливает группу, упавший тест - останавливает
+		expectNext := StatusPassed
+		if c.status == StatusFailed {
+			expectNext = StatusSkipped
+		}
+		assert.Equal(t, expectNext, result.Cases[1].Status, c.Name)
+	}
+}
@@ -0,0 +1,161 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test/validators/jsondiff"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonpath"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// SnapshotDir - папка со снимками ответов рядом с файлами тестов
+const SnapshotDir = "__snapshots__"
+
+// ignoredValue заменяет в снимке значения по путям из snapshot.ignore
+const ignoredValue = "<ignored>"
+
+var snapshotNameRe = regexp.MustCompile(`[^\w.-]+`)
+
+// validSnapshot сравнивает тело ответа и websocket сообщение теста с сохраненным снимком.
+// Если снимка еще нет или запущено обновление снимков, снимок записывается и проверка проходит.
+func (r *RunnerGroup) validSnapshot(logger zerolog.Logger, test Case) bool {
+	if r.result == nil || (r.result.Response == nil && r.result.Message == nil) {
+		return true
+	}
+
+	path := snapshotPath(test)
+	logger = logger.With().Str("validator", "snapshot").Str("snapshot", path).Logger()
+	rule := rules.Rule{Key: filepath.Join(SnapshotDir, filepath.Base(path))}
+
+	actual, err := takeSnapshot(r.result, test.Snapshot.Ignore)
+	if err != nil {
+		return r.errored(logger, fmt.Errorf("snapshot: %w", err))
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if r.updateSnapshots || errors.Is(err, os.ErrNotExist) {
+		if err = writeSnapshot(path, actual); err != nil {
+			return r.errored(logger, fmt.Errorf("writing snapshot: %w", err))
+		}
+
+		logger.Info().Msg("snapshot written")
+
+		return r.ruleChecked(logger, "snapshot", 0, rule, nil)
+	}
+	if err != nil {
+		return r.errored(logger, fmt.Errorf("reading snapshot: %w", err))
+	}
+
+	decoded, err := jsonschema.Decode(b)
+	if err != nil {
+		return r.errored(logger, fmt.Errorf("decoding snapshot '%s': %w", path, err))
+	}
+
+	expected, ok := decoded.(map[string]interface{})
+	if !ok {
+		return r.errored(logger, fmt.Errorf("decoding snapshot '%s': snapshot must be an object", path))
+	}
+
+	// Пути могли добавить в ignore после записи снимка
+	if err = ignorePaths(expected, test.Snapshot.Ignore); err != nil {
+		return r.errored(logger, fmt.Errorf("snapshot: %w", err))
+	}
+
+	changes := jsondiff.Diff(expected, actual)
+	if len(changes) == 0 {
+		return r.ruleChecked(logger, "snapshot", 0, rule, nil)
+	}
+
+	diff := jsondiff.String(changes)
+	logger = logger.With().RawJSON("diff", []byte(diff)).Logger()
+
+	return r.ruleChecked(logger, "snapshot", 0, rule, fmt.Errorf("snapshot mismatch: %s", diff))
+}
+
+// snapshotPath возвращает путь к снимку теста: __snapshots__/<имя файла теста>.json
+func snapshotPath(test Case) string {
+	name := filepath.Base(test.Filename)
+	if ext := filepath.Ext(name); ext == ".yml" || ext == ".yaml" {
+		name = strings.TrimSuffix(name, ext)
+	}
+	name = strings.Trim(snapshotNameRe.ReplaceAllString(name, "-"), "-")
+
+	return filepath.Join(test.Dir, SnapshotDir, name+".json")
+}
+
+// takeSnapshot собирает снимок из ответа и сообщения теста.
+// Тело, которое не является JSON, сохраняется строкой.
+func takeSnapshot(result *CaseResult, ignore []string) (map[string]interface{}, error) {
+	snapshot := make(map[string]interface{})
+
+	if result.Response != nil {
+		snapshot["body"] = snapshotValue(result.Response.Body)
+	}
+	if result.Message != nil {
+		snapshot["message"] = snapshotValue(*result.Message)
+	}
+
+	if err := ignorePaths(snapshot, ignore); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func snapshotValue(data string) interface{} {
+	value, err := jsonschema.Decode([]byte(data))
+	if err != nil {
+		return data
+	}
+
+	return value
+}
+
+// ignorePaths заменяет изменчивые значения тела и сообщения на заглушку
+func ignorePaths(snapshot map[string]interface{}, ignore []string) error {
+	for _, p := range ignore {
+		path, err := jsonpath.Parse(p)
+		if err != nil {
+			return fmt.Errorf("ignore: %w", err)
+		}
+
+		for _, key := range []string{"body", "message"} {
+			if value, ok := snapshot[key]; ok {
+				path.Replace(value, ignoredValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeSnapshot записывает снимок с отступами и ключами по алфавиту,
+// чтобы изменения снимков было удобно смотреть в истории
+func writeSnapshot(path string, snapshot map[string]interface{}) error {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(snapshot); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0o644)
+}
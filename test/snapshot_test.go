@@ -0,0 +1,96 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestSnapshotPath(t *testing.T) {
+	cases := []struct {
+		Filename string
+		Expect   string
+	}{
+		{Filename: "1-create.yml", Expect: "1-create.json"},
+		{Filename: "2-get report.yaml", Expect: "2-get-report.json"},
+		{Filename: "fixtures/3-list.yml", Expect: "3-list.json"},
+		{Filename: "init.yml: setup[0]", Expect: "init.yml-setup-0.json"},
+	}
+
+	for _, c := range cases {
+		path := snapshotPath(Case{Filename: c.Filename, Dir: "tests"})
+		assert.Equal(t, filepath.Join("tests", SnapshotDir, c.Expect), path, c.Filename)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	body := `{"id": 1, "name": "Отчет"}`
+	code := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, SnapshotDir, "1-get.json")
+	ok := "200"
+
+	snapshotCase := func(ignore ...string) Case {
+		return Case{
+			Name:     "Получение отчета",
+			Filename: "1-get.yml",
+			Dir:      dir,
+			Request:  Request{URL: srv.URL, Method: http.MethodGet},
+			Response: Response{Code: []rules.Rule{{Equal: &ok}}},
+			Snapshot: &Snapshot{Ignore: ignore},
+		}
+	}
+	run := func(test Case, update bool) CaseResult {
+		runner := NewRunnerGroup(Group{}, zerolog.Nop(), nil)
+		runner.updateSnapshots = update
+
+		return runner.Run(test)
+	}
+
+	// Ответ упавшего теста не записывается
+	code = http.StatusInternalServerError
+	result := run(snapshotCase(), false)
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.NoFileExists(t, path)
+	code = http.StatusOK
+
+	// Первый запуск записывает снимок
+	result = run(snapshotCase(), false)
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+
+	b, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"body\": {\n    \"id\": 1,\n    \"name\": \"Отчет\"\n  }\n}\n", string(b))
+
+	// Изменившийся ответ не проходит проверку
+	body = `{"id": 2, "name": "Отчет"}`
+	result = run(snapshotCase(), false)
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Equal(t, []string{`snapshot mismatch: [{"op":"replace","path":"/body/id","expected":1,"actual":2}]`}, result.Errors)
+
+	// Путь из ignore применяется и к снимку, записанному раньше
+	result = run(snapshotCase("$.id"), false)
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+
+	// Обновление перезаписывает снимок
+	result = run(snapshotCase("$.id"), true)
+	assert.Equal(t, StatusPassed, result.Status, result.Errors)
+
+	b, err = os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "{\n  \"body\": {\n    \"id\": \"<ignored>\",\n    \"name\": \"Отчет\"\n  }\n}\n", string(b))
+}
@@ -13,6 +13,18 @@ func NewBodyValidator(store *store.Store, validator ValidatorDescr) (BodyValidat
 		return NewJSONValidator(store), nil
 	case "string":
 		return NewStringValidator(store), nil
+	case "jsonschema":
+		return NewJSONSchemaValidator(store, validator)
+	case "yaml":
+		return NewYAMLValidator(store), nil
+	case "xml":
+		return NewXMLValidator(store), nil
+	case "html":
+		return NewHTMLValidator(store), nil
+	case "csv":
+		return NewCSVValidator(store, validator)
+	case "bytes":
+		return NewBytesValidator(store), nil
 	default:
 		return nil, fmt.Errorf("invalid validator type %s", validator.Type)
 	}
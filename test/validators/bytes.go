@@ -0,0 +1,74 @@
+package validators
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// magicSize - сколько первых байт ответа попадает в ключ magic
+const magicSize = 16
+
+type Bytes struct {
+	StoreBase
+}
+
+// NewBytesValidator возвращает валидатор для бинарного ответа: файлов, архивов, картинок.
+// Ключи правил: size, sha256, md5, magic (первые байты в hex), mime.
+func NewBytesValidator(store *store.Store) *Bytes {
+	return &Bytes{
+		StoreBase{store: store},
+	}
+}
+
+func (b *Bytes) ValidBody(rule rules.Rule, body []byte) error {
+	// Размер - число, указывать тип для него не обязательно
+	if rule.Key == "size" && rule.Type == rules.TypeEmpty {
+		rule.Type = rules.TypeInteger
+	}
+
+	var err error
+	rule, err = b.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	var value string
+	switch rule.Key {
+	case "size":
+		return b.validSize(rule, len(body))
+	case "sha256":
+		sum := sha256.Sum256(body)
+		value = hex.EncodeToString(sum[:])
+	case "md5":
+		sum := md5.Sum(body)
+		value = hex.EncodeToString(sum[:])
+	case "magic":
+		magic := body
+		if len(magic) > magicSize {
+			magic = magic[:magicSize]
+		}
+		value = hex.EncodeToString(magic)
+	case "mime":
+		value = http.DetectContentType(body)
+	default:
+		return fmt.Errorf("invalid bytes key '%s', expected size, sha256, md5, magic or mime", rule.Key)
+	}
+
+	return b.validFound(rule, []found{{path: rule.Key, value: value}})
+}
+
+// validSize проверяет размер ответа как число
+func (b *Bytes) validSize(rule rules.Rule, size int) error {
+	if err := rule.Valid(size); err != nil {
+		return fmt.Errorf("field 'size': %w", err)
+	}
+
+	return b.storeSave(rule, size)
+}
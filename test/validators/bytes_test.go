@@ -0,0 +1,36 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestBytes(t *testing.T) {
+	var10 := "10"
+	var14 := "14"
+	varMagic := "25504446"
+	varMime := "application/pdf"
+	varSHA := "f31cdee0b4d4fdae0638872f6bb7d0e6ee041386a9055d5e64c25d9d35dc88d1"
+	varSize := "size"
+
+	s := store.NewStore(nil)
+	validator := NewBytesValidator(s)
+	body := []byte("%PDF-1.4 hello")
+
+	checkBodyCases(t, validator, body, []bodyCase{
+		{Name: "Размер без типа - число", rule: rules.Rule{Key: "size", Equal: &var14, Store: &varSize}},
+		{Name: "Размер больше допустимого", rule: rules.Rule{Key: "size", Less: &var10}, expectErr: "field 'size': must less then '10'"},
+		{Name: "Сигнатура файла", rule: rules.Rule{Key: "magic", Prefix: &varMagic}},
+		{Name: "Тип содержимого", rule: rules.Rule{Key: "mime", Equal: &varMime}},
+		{Name: "Хеш", rule: rules.Rule{Type: "hex", Key: "sha256", Equal: &varSHA}},
+		{Name: "Хеш не совпадает", rule: rules.Rule{Key: "md5", Equal: &varSHA}, expectErr: "field 'md5': must be '" + varSHA + "'"},
+		{Name: "Неизвестный ключ", rule: rules.Rule{Key: "crc32"}, expectErr: "invalid bytes key 'crc32', expected size, sha256, md5, magic or mime"},
+	})
+
+	size, _ := s.Get("size")
+	assert.Equal(t, 14, size)
+}
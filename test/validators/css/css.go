@@ -0,0 +1,85 @@
+// Package css ищет элементы HTML документа по CSS селекторам.
+//
+// Селекторы разбирает библиотека cascadia, поддерживается синтаксис CSS Selectors Level 3:
+// теги, #id, .class, атрибуты, комбинаторы " ", ">", "+", "~", :nth-child, :not, :has,
+// :contains и другие псевдоклассы, несколько селекторов через запятую.
+package css
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// Selector - разобранный CSS селектор
+type Selector struct {
+	group cascadia.SelectorGroup
+}
+
+// Parse разбирает CSS селектор
+func Parse(selector string) (*Selector, error) {
+	group, err := cascadia.ParseGroup(selector)
+	if err != nil {
+		return nil, fmt.Errorf("parsing css selector '%s': %w", selector, err)
+	}
+
+	return &Selector{group: group}, nil
+}
+
+// ParseDocument разбирает HTML документ
+func ParseDocument(data []byte) (*html.Node, error) {
+	doc, err := html.Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("parsing html: %w", err)
+	}
+
+	return doc, nil
+}
+
+// Find возвращает элементы документа, подходящие под селектор, в порядке их следования
+func (s *Selector) Find(doc *html.Node) []*html.Node {
+	return cascadia.QueryAll(doc, s.group)
+}
+
+// Match проверяет, подходит ли элемент под селектор
+func (s *Selector) Match(n *html.Node) bool {
+	return s.group.Match(n)
+}
+
+// Attr возвращает значение атрибута элемента
+func Attr(n *html.Node, name string) (string, bool) {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// Text возвращает текст элемента вместе с вложенными элементами.
+// Пробелы и переносы строк схлопываются в один пробел.
+func Text(n *html.Node) string {
+	var sb strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		case html.ElementNode:
+			if n.Data == "script" || n.Data == "style" {
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
@@ -0,0 +1,149 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `<!DOCTYPE html>
+<html>
+<head><title>Отчет</title></head>
+<body>
+  <h1 id="title">Отчет   по
+    заправкам</h1>
+  <ul class="list">
+    <li class="item active"><a href="https://example.com/1.pdf">Первый</a></li>
+    <li class="item"><a href="/2" lang="ru-RU">Второй</a></li>
+    <li class="item"><span><a href="/3">Третий</a></span></li>
+  </ul>
+  <p class="note">Итого: <b>3</b></p>
+  <p class="note empty"></p>
+  <form><input type="text" name="login"><input type="password" name="pass" disabled></form>
+  <script>var title = "скрипт";</script>
+</body>
+</html>`
+
+func TestFind(t *testing.T) {
+	doc, err := ParseDocument([]byte(document))
+	assert.Nil(t, err)
+
+	cases := []struct {
+		Selector string
+		Expect   []string
+	}{
+		// Тег, id, класс
+		{Selector: "h1", Expect: []string{"Отчет по заправкам"}},
+		{Selector: "#title", Expect: []string{"Отчет по заправкам"}},
+		{Selector: "H1", Expect: []string{"Отчет по заправкам"}},
+		{Selector: "li.item.active", Expect: []string{"Первый"}},
+		{Selector: ".note", Expect: []string{"Итого: 3", ""}},
+		{Selector: "*#title", Expect: []string{"Отчет по заправкам"}},
+
+		// Комбинаторы
+		{Selector: "ul.list li.active", Expect: []string{"Первый"}},
+		{Selector: ".list a", Expect: []string{"Первый", "Второй", "Третий"}},
+		{Selector: ".list > li > a", Expect: []string{"Первый", "Второй"}},
+		{Selector: ".list>li>a", Expect: []string{"Первый", "Второй"}},
+		{Selector: "li.active + li", Expect: []string{"Второй"}},
+		{Selector: "li.active ~ li", Expect: []string{"Второй", "Третий"}},
+		{Selector: "h1, p b", Expect: []string{"Отчет по заправкам", "3"}},
+
+		// Атрибуты
+		{Selector: "a[href^=https]", Expect: []string{"Первый"}},
+		{Selector: "a[href$='.pdf']", Expect: []string{"Первый"}},
+		{Selector: `a[href*="example"]`, Expect: []string{"Первый"}},
+		{Selector: "a[href='/2']", Expect: []string{"Второй"}},
+		{Selector: "li[class~=active]", Expect: []string{"Первый"}},
+		{Selector: "a[lang|=ru]", Expect: []string{"Второй"}},
+		{Selector: "input[disabled]", Expect: []string{""}},
+
+		// Псевдоклассы
+		{Selector: "li:first-child, li:last-child", Expect: []string{"Первый", "Третий"}},
+		{Selector: "li:nth-child(2)", Expect: []string{"Второй"}},
+		{Selector: "li:nth-child(odd)", Expect: []string{"Первый", "Третий"}},
+		{Selector: "li:nth-child(even)", Expect: []string{"Второй"}},
+		{Selector: "li:nth-child(2n+1)", Expect: []string{"Первый", "Третий"}},
+		{Selector: "li:nth-last-child(1)", Expect: []string{"Третий"}},
+		{Selector: "p:nth-of-type(2)", Expect: []string{""}},
+		{Selector: "p:first-of-type", Expect: []string{"Итого: 3"}},
+		{Selector: "span > a:only-child", Expect: []string{"Третий"}},
+		{Selector: "p:empty", Expect: []string{""}},
+		{Selector: "li:not(.active)", Expect: []string{"Второй", "Третий"}},
+		{Selector: "li:has(span)", Expect: []string{"Третий"}},
+		{Selector: "li:contains('Втор')", Expect: []string{"Второй"}},
+
+		// Ничего не найдено
+		{Selector: "table td", Expect: nil},
+		{Selector: "li:hover", Expect: nil},
+	}
+
+	for _, c := range cases {
+		sel, err := Parse(c.Selector)
+		if !assert.Nil(t, err, c.Selector) {
+			continue
+		}
+
+		var texts []string
+		for _, n := range sel.Find(doc) {
+			texts = append(texts, Text(n))
+		}
+
+		assert.Equal(t, c.Expect, texts, c.Selector)
+	}
+}
+
+func TestAttr(t *testing.T) {
+	doc, err := ParseDocument([]byte(document))
+	assert.Nil(t, err)
+
+	sel, err := Parse("input[type=password]")
+	assert.Nil(t, err)
+
+	found := sel.Find(doc)
+	if assert.Len(t, found, 1) {
+		name, ok := Attr(found[0], "NAME")
+		assert.True(t, ok)
+		assert.Equal(t, "pass", name)
+
+		_, ok = Attr(found[0], "value")
+		assert.False(t, ok)
+
+		assert.True(t, sel.Match(found[0]))
+	}
+}
+
+func TestText(t *testing.T) {
+	doc, err := ParseDocument([]byte(document))
+	assert.Nil(t, err)
+
+	sel, err := Parse("body")
+	assert.Nil(t, err)
+
+	// Текст скриптов не попадает в значение элемента
+	found := sel.Find(doc)
+	if assert.Len(t, found, 1) {
+		assert.NotContains(t, Text(found[0]), "скрипт")
+		assert.Contains(t, Text(found[0]), "Первый Второй Третий Итого: 3")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		Selector  string
+		ExpectErr string
+	}{
+		{Selector: "", ExpectErr: "parsing css selector '': expected selector, found EOF instead"},
+		{Selector: "div,", ExpectErr: "parsing css selector 'div,': expected selector, found EOF instead"},
+		{Selector: "div > ", ExpectErr: "parsing css selector 'div > ': expected selector, found EOF instead"},
+		{Selector: "a[href", ExpectErr: "parsing css selector 'a[href': unexpected EOF in attribute selector"},
+		{Selector: "#", ExpectErr: "parsing css selector '#': expected name, found EOF instead"},
+		{Selector: "li:nth-child(x)", ExpectErr: "parsing css selector 'li:nth-child(x)': unexpected character while attempting to parse expression of form an+b"},
+		{Selector: "li::before", ExpectErr: "parsing css selector 'li::before': pseudo-element before found, but pseudo-elements support is disabled"},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.Selector)
+		assert.EqualError(t, err, c.ExpectErr, c.Selector)
+	}
+}
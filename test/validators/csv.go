@@ -0,0 +1,176 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type CSV struct {
+	StoreBase
+	delimiter rune
+}
+
+// NewCSVValidator возвращает валидатор для CSV.
+// Первая строка файла - заголовок, строки данных нумеруются с нуля.
+func NewCSVValidator(store *store.Store, validator ValidatorDescr) (*CSV, error) {
+	delimiter := ','
+	if validator.Delimiter != "" {
+		if utf8.RuneCountInString(validator.Delimiter) != 1 {
+			return nil, fmt.Errorf("csv delimiter must be a single character, got '%s'", validator.Delimiter)
+		}
+
+		delimiter, _ = utf8.DecodeRuneInString(validator.Delimiter)
+	}
+
+	return &CSV{
+		StoreBase: StoreBase{store: store},
+		delimiter: delimiter,
+	}, nil
+}
+
+// ValidBody проверяет значения по ключу правила:
+//   - rows - строки данных, header - ячейки заголовка
+//   - 2 - строка данных целиком, -1 - последняя строка
+//   - 2.name, 2.0, *.name - ячейка по номеру строки и имени или номеру колонки
+func (c *CSV) ValidBody(rule rules.Rule, body []byte) error {
+	var err error
+	rule, err = c.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	if rule.Key == "" {
+		return c.validFound(rule, []found{{value: string(body)}})
+	}
+
+	records, err := c.read(body)
+	if err != nil {
+		return err
+	}
+
+	var header []string
+	if len(records) > 0 {
+		header, records = records[0], records[1:]
+	}
+
+	values, err := c.find(rule.Key, header, records)
+	if err != nil {
+		return err
+	}
+
+	return c.validFound(rule, values)
+}
+
+func (c *CSV) read(body []byte) ([][]string, error) {
+	// BOM часто добавляют при выгрузке из Excel
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+
+	r := csv.NewReader(bytes.NewReader(body))
+	r.Comma = c.delimiter
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing csv: %w", err)
+	}
+
+	return records, nil
+}
+
+func (c *CSV) find(key string, header []string, records [][]string) ([]found, error) {
+	switch key {
+	case "header":
+		var values []found
+		for i, cell := range header {
+			values = append(values, found{path: fmt.Sprintf("header.%d", i), value: cell})
+		}
+		return values, nil
+	case "rows":
+		var values []found
+		for i, record := range records {
+			values = append(values, found{path: strconv.Itoa(i), value: c.join(record)})
+		}
+		return values, nil
+	}
+
+	rowKey, colKey, hasCol := strings.Cut(key, ".")
+
+	rows, err := rowIndexes(rowKey, len(records))
+	if err != nil {
+		return nil, fmt.Errorf("csv key '%s': %w", key, err)
+	}
+
+	var values []found
+	if !hasCol {
+		for _, row := range rows {
+			values = append(values, found{path: strconv.Itoa(row), value: c.join(records[row])})
+		}
+		return values, nil
+	}
+
+	col, err := columnIndex(colKey, header)
+	if err != nil {
+		return nil, fmt.Errorf("csv key '%s': %w", key, err)
+	}
+
+	for _, row := range rows {
+		if col < len(records[row]) {
+			values = append(values, found{path: fmt.Sprintf("%d.%s", row, colKey), value: records[row][col]})
+		}
+	}
+
+	return values, nil
+}
+
+// rowIndexes возвращает номера строк: * - все строки, отрицательный номер - с конца
+func rowIndexes(key string, count int) ([]int, error) {
+	if key == "*" {
+		rows := make([]int, count)
+		for i := range rows {
+			rows[i] = i
+		}
+		return rows, nil
+	}
+
+	row, err := strconv.Atoi(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid row '%s'", key)
+	}
+
+	if row < 0 {
+		row += count
+	}
+	if row < 0 || row >= count {
+		return nil, nil
+	}
+
+	return []int{row}, nil
+}
+
+// columnIndex ищет колонку по имени в заголовке, затем по номеру
+func columnIndex(key string, header []string) (int, error) {
+	for i, name := range header {
+		if strings.TrimSpace(name) == key {
+			return i, nil
+		}
+	}
+
+	if col, err := strconv.Atoi(key); err == nil && col >= 0 {
+		return col, nil
+	}
+
+	return 0, fmt.Errorf("column '%s' not found in header", key)
+}
+
+func (c *CSV) join(record []string) string {
+	return strings.Join(record, string(c.delimiter))
+}
@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestCSV(t *testing.T) {
+	var0 := "0"
+	var2 := "2"
+	var3 := "3"
+	varID := "id"
+	varIvan := "Ivan"
+	varRoman := "Roman"
+	varRow := "2;Roman;3"
+
+	validator, err := NewCSVValidator(store.NewStore(nil), ValidatorDescr{Delimiter: ";"})
+	assert.Nil(t, err)
+
+	// BOM в начале файла, как в выгрузках из Excel
+	body := []byte("\xef\xbb\xbfid;name;sum\n1;Ivan;10\n2;Roman;3\n")
+
+	checkBodyCases(t, validator, body, []bodyCase{
+		{Name: "Ячейки заголовка, BOM отброшен", rule: rules.Rule{Type: "array", Key: "header", Count: &var3, Contains: &varID}},
+		{Name: "Количество строк данных", rule: rules.Rule{Type: "array", Key: "rows", Count: &var2}},
+		{Name: "Ячейка по имени колонки", rule: rules.Rule{Key: "0.name", Equal: &varIvan}},
+		{Name: "Ячейка по номеру колонки", rule: rules.Rule{Key: "1.1", Equal: &varRoman}},
+		{Name: "Последняя строка", rule: rules.Rule{Key: "-1.name", Equal: &varRoman}},
+		{Name: "Строка целиком", rule: rules.Rule{Key: "-1", Equal: &varRow}},
+		{Name: "Колонка во всех строках", rule: rules.Rule{Type: "integer", Key: "*.sum", Greater: &var0}},
+		{Name: "Колонка во всех строках с ошибкой", rule: rules.Rule{Type: "integer", Key: "*.sum", Greater: &var3}, expectErr: "field '1.sum': must greater then '3'"},
+		{Name: "Несуществующая строка", rule: rules.Rule{Key: "5.name"}, expectErr: "field '5.name': required not exists"},
+		{Name: "Несуществующая колонка", rule: rules.Rule{Key: "0.email"}, expectErr: "csv key '0.email': column 'email' not found in header"},
+		{Name: "Неверный номер строки", rule: rules.Rule{Key: "first.name"}, expectErr: "csv key 'first.name': invalid row 'first'"},
+	})
+
+	_, err = NewCSVValidator(store.NewStore(nil), ValidatorDescr{Delimiter: ";;"})
+	assert.EqualError(t, err, "csv delimiter must be a single character, got ';;'")
+}
@@ -0,0 +1,117 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+// found - значение, найденное в документе (XML, HTML, CSV), и путь к нему.
+// Путь пустой, если проверяется весь документ.
+type found struct {
+	path  string
+	value string
+}
+
+// validFound проверяет значения, найденные в документе по ключу правила.
+// Правилу должно соответствовать каждое значение, для типа array - список значений целиком.
+func (s StoreBase) validFound(rule rules.Rule, values []found) error {
+	fail := func(path string, err error) error {
+		if path == "" {
+			return err
+		}
+
+		return fmt.Errorf("field '%s': %w", path, err)
+	}
+
+	switch {
+	case rule.Absent != nil && *rule.Absent:
+		if len(values) > 0 {
+			return fail(values[0].path, rule.Valid(values[0].value))
+		}
+
+		return nil
+	case rule.Type == rules.TypeArray:
+		items := make([][]byte, 0, len(values))
+		for _, v := range values {
+			b, err := json.Marshal(v.value)
+			if err != nil {
+				return fmt.Errorf("encoding '%s': %w", v.path, err)
+			}
+
+			items = append(items, b)
+		}
+
+		if err := rule.Valid(items); err != nil {
+			return fail(rule.Key, err)
+		}
+
+		// Вложенные правила без ключа проверяют каждый элемент
+		for _, subRule := range rule.Fields {
+			if subRule.Key != "" {
+				continue
+			}
+
+			var err error
+			if subRule, err = s.prepareRule(subRule); err != nil {
+				return err
+			}
+
+			for _, v := range values {
+				if err = s.validFound(subRule, []found{v}); err != nil {
+					return err
+				}
+			}
+		}
+	case len(values) == 0:
+		if err := rule.Valid(nil); err != nil {
+			return fail(rule.Key, err)
+		}
+
+		return nil
+	default:
+		for _, v := range values {
+			value := typedValue(rule.Type, v.value)
+
+			if err := rule.Valid(value); err != nil {
+				return fail(v.path, err)
+			}
+
+			if err := s.storeCaptures(rule, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Одно значение сохраняется как есть, несколько - массивом
+	if len(values) == 1 && rule.Type != rules.TypeArray {
+		return s.storeSave(rule, values[0].value)
+	}
+
+	list := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		list = append(list, v.value)
+	}
+
+	return s.storeSave(rule, list)
+}
+
+// typedValue приводит найденный текст к типу правила.
+// Если привести не удалось, возвращается текст, и правило сообщит о неверном типе.
+func typedValue(typo rules.RuleType, value string) interface{} {
+	switch typo {
+	case rules.TypeInteger, rules.TypeFloat:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return f
+		}
+	case rules.TypeBoolean:
+		if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
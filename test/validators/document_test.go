@@ -0,0 +1,149 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type bodyCase struct {
+	Name      string
+	rule      rules.Rule
+	expectErr string
+}
+
+func checkBodyCases(t *testing.T, validator BodyValidator, body []byte, cases []bodyCase) {
+	for _, c := range cases {
+		err := validator.ValidBody(c.rule, body)
+		if c.expectErr == "" {
+			assert.Nil(t, err, c.Name)
+		} else {
+			assert.EqualError(t, err, c.expectErr, c.Name)
+		}
+	}
+}
+
+func TestValidFound(t *testing.T) {
+	varTrue := true
+	varFalse := false
+	var2 := "2"
+	var3 := "3"
+	var5 := "5"
+	varA := "a"
+	varTrueStr := "true"
+	varGroup := `^(?P<letter>[a-z])$`
+	storeOne := "one"
+	storeMany := "many"
+	storeList := "list"
+
+	s := store.NewStore(nil)
+	v := StoreBase{store: s}
+
+	values := []found{{path: "/a", value: "a"}, {path: "/b", value: "b"}}
+
+	cases := []struct {
+		Name      string
+		rule      rules.Rule
+		values    []found
+		expectErr string
+	}{
+		{
+			Name:   "absent без значений",
+			rule:   rules.Rule{Type: "string", Key: "//x", Absent: &varTrue},
+			values: nil,
+		},
+		{
+			Name:      "absent со значением",
+			rule:      rules.Rule{Type: "string", Key: "//a", Absent: &varTrue},
+			values:    values,
+			expectErr: "field '/a': must be absent",
+		},
+		{
+			Name:      "Обязательное значение не найдено",
+			rule:      rules.Rule{Type: "string", Key: "//x"},
+			expectErr: "field '//x': required not exists",
+		},
+		{
+			Name: "Необязательное значение не найдено",
+			rule: rules.Rule{Type: "string", Key: "//x", Required: &varFalse},
+		},
+		{
+			Name:      "Каждое значение проверяется правилом",
+			rule:      rules.Rule{Type: "string", Key: "//*", Equal: &varA},
+			values:    values,
+			expectErr: "field '/b': must be 'a'",
+		},
+		{
+			Name:   "array - количество значений",
+			rule:   rules.Rule{Type: "array", Key: "//*", Count: &var2, Contains: &varA},
+			values: values,
+		},
+		{
+			Name:      "array - неверное количество",
+			rule:      rules.Rule{Type: "array", Key: "//*", Count: &var3},
+			values:    values,
+			expectErr: "field '//*': must have 3 items, got 2",
+		},
+		{
+			Name:   "array без значений",
+			rule:   rules.Rule{Type: "array", Key: "//x", MaxItems: &var2, Store: &storeList},
+			values: nil,
+		},
+		{
+			Name:      "array - вложенные правила без ключа проверяют каждый элемент",
+			rule:      rules.Rule{Type: "array", Key: "//*", Fields: []rules.Rule{{Equal: &varA}}},
+			values:    values,
+			expectErr: "field '/b': must be 'a'",
+		},
+		{
+			Name:   "Число из текста",
+			rule:   rules.Rule{Type: "integer", Key: "/n", Less: &var5},
+			values: []found{{path: "/n", value: " 3 "}},
+		},
+		{
+			Name:      "Текст, который не является числом",
+			rule:      rules.Rule{Type: "integer", Key: "/n"},
+			values:    []found{{path: "/n", value: "три"}},
+			expectErr: "field '/n': is not an integer",
+		},
+		{
+			Name:   "Булево значение из текста",
+			rule:   rules.Rule{Type: "boolean", Key: "/b", Equal: &varTrueStr},
+			values: []found{{path: "/b", value: "true"}},
+		},
+		{
+			Name:   "Одно значение сохраняется как есть",
+			rule:   rules.Rule{Type: "string", Key: "/a", Store: &storeOne, Regex: &varGroup},
+			values: values[:1],
+		},
+		{
+			Name:   "Несколько значений сохраняются массивом",
+			rule:   rules.Rule{Type: "string", Key: "//*", Store: &storeMany},
+			values: values,
+		},
+	}
+
+	for _, c := range cases {
+		err := v.validFound(c.rule, c.values)
+		if c.expectErr == "" {
+			assert.Nil(t, err, c.Name)
+		} else {
+			assert.EqualError(t, err, c.expectErr, c.Name)
+		}
+	}
+
+	one, _ := s.Get("one")
+	assert.Equal(t, "a", one)
+
+	letter, _ := s.Get("letter")
+	assert.Equal(t, "a", letter)
+
+	many, _ := s.Get("many")
+	assert.Equal(t, store.Array{"a", "b"}, many)
+
+	list, _ := s.Get("list")
+	assert.Equal(t, store.Array{}, list)
+}
@@ -28,13 +28,25 @@ func (h *Header) ValidHeader(rule rules.Rule, headers http.Header) (error, strin
 	}
 
 	val := headers.Get(rule.Key)
-	err = rule.Valid(val)
+
+	// Отсутствующий заголовок для absent - это отсутствие значения, а не пустая строка
+	if _, ok := headers[http.CanonicalHeaderKey(rule.Key)]; !ok && rule.Absent != nil && *rule.Absent {
+		err = rule.Valid(nil)
+	} else {
+		err = rule.Valid(val)
+	}
 
 	if err != nil {
 		return fmt.Errorf("field '%s': %w", rule.Key, err), ""
 	}
 
-	h.storeSave(rule, val)
+	if err = h.storeSave(rule, val); err != nil {
+		return err, ""
+	}
+
+	if err = h.storeCaptures(rule, val); err != nil {
+		return err, ""
+	}
 
 	return nil, val
 }
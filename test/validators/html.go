@@ -0,0 +1,76 @@
+package validators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/css"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type HTML struct {
+	StoreBase
+}
+
+// NewHTMLValidator возвращает валидатор для HTML.
+// Ключ правила - CSS селектор, значение элемента - его текст.
+// Атрибут указывается после селектора через @, например: a.download@href
+func NewHTMLValidator(store *store.Store) *HTML {
+	return &HTML{
+		StoreBase{store: store},
+	}
+}
+
+func (h *HTML) ValidBody(rule rules.Rule, body []byte) error {
+	var err error
+	rule, err = h.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	if rule.Key == "" {
+		return h.validFound(rule, []found{{value: string(body)}})
+	}
+
+	selector, attr := splitAttr(rule.Key)
+
+	sel, err := css.Parse(selector)
+	if err != nil {
+		return err
+	}
+
+	doc, err := css.ParseDocument(body)
+	if err != nil {
+		return err
+	}
+
+	nodes := sel.Find(doc)
+
+	var values []found
+	for i, n := range nodes {
+		path := rule.Key
+		if len(nodes) > 1 {
+			path = fmt.Sprintf("%s[%d]", rule.Key, i)
+		}
+
+		if attr == "" {
+			values = append(values, found{path: path, value: css.Text(n)})
+		} else if value, ok := css.Attr(n, attr); ok {
+			values = append(values, found{path: path, value: value})
+		}
+	}
+
+	return h.validFound(rule, values)
+}
+
+// splitAttr отделяет имя атрибута от селектора: a.download@href
+func splitAttr(key string) (string, string) {
+	i := strings.LastIndexByte(key, '@')
+	if i < 0 || strings.ContainsAny(key[i:], "]) ") {
+		return key, ""
+	}
+
+	return key[:i], key[i+1:]
+}
@@ -0,0 +1,33 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestHTML(t *testing.T) {
+	varTrue := true
+	var2 := "2"
+	varTitle := "Отчет по заправкам"
+	varPDF := ".pdf"
+	varFirst := "Первый"
+
+	validator := NewHTMLValidator(store.NewStore(nil))
+	body := []byte(`<html><body>
+		<h1>Отчет   по
+		заправкам</h1>
+		<ul><li><a class="download" href="/1.pdf">Первый</a></li><li><a href="/2">Второй</a></li></ul>
+	</body></html>`)
+
+	checkBodyCases(t, validator, body, []bodyCase{
+		{Name: "Текст без лишних пробелов", rule: rules.Rule{Key: "h1", Equal: &varTitle}},
+		{Name: "Атрибут", rule: rules.Rule{Key: "a.download@href", Suffix: &varPDF}},
+		{Name: "Количество элементов", rule: rules.Rule{Type: "array", Key: "ul > li", Count: &var2}},
+		{Name: "Каждый найденный элемент", rule: rules.Rule{Key: "li a", Equal: &varFirst}, expectErr: "field 'li a[1]': must be 'Первый'"},
+		{Name: "Элемент без атрибута", rule: rules.Rule{Key: "a@title", Absent: &varTrue}},
+		{Name: "Отсутствующий элемент", rule: rules.Rule{Key: "table"}, expectErr: "field 'table': required not exists"},
+		{Name: "Неверный селектор", rule: rules.Rule{Key: "a[href"}, expectErr: "parsing css selector 'a[href': unexpected EOF in attribute selector"},
+	})
+}
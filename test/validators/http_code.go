@@ -1,8 +1,6 @@
 package validators
 
 import (
-	"strconv"
-
 	"github.com/MashinaMashina/api-tests/store"
 	"github.com/MashinaMashina/api-tests/test/validators/rules"
 )
@@ -26,7 +24,13 @@ func (h *HTTPCode) ValidHTTPCode(rule rules.Rule, code int) error {
 		return err
 	}
 
-	h.storeSave(rule, strconv.Itoa(code))
+	if err = h.storeSave(rule, code); err != nil {
+		return err
+	}
+
+	if err = rule.Valid(code); err != nil {
+		return err
+	}
 
-	return rule.Valid(code)
+	return h.storeCaptures(rule, code)
 }
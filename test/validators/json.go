@@ -1,13 +1,17 @@
 package validators
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/buger/jsonparser"
 
 	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonpath"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
 	"github.com/MashinaMashina/api-tests/test/validators/rules"
 )
 
@@ -31,6 +35,15 @@ func (j *JSON) ValidBody(rule rules.Rule, body []byte) error {
 		return err
 	}
 
+	if jsonpath.IsPath(rule.Key) {
+		return j.validPath(rule, body)
+	}
+
+	// Для absent тип не важен, проверяется только наличие значения
+	if rule.Absent != nil && *rule.Absent {
+		return j.validAbsent(rule, body)
+	}
+
 	value, err := j.getValue(rule.Type, rule.Key, body)
 
 	if err != nil {
@@ -38,21 +51,54 @@ func (j *JSON) ValidBody(rule rules.Rule, body []byte) error {
 	}
 
 	if err = rule.Valid(value); err != nil {
+		// Без ключа проверяется само значение, имя поля добавит вызывающий
+		if rule.Key == "" {
+			return err
+		}
+
 		return fmt.Errorf("field '%s': %w", rule.Key, err)
 	}
 
-	if rule.Store != nil {
-		bytes, _, _, err := jsonparser.Get(body, rule.Key)
+	// Необязательного значения нет в ответе, проверять и сохранять нечего
+	if value == nil {
+		return nil
+	}
 
+	if err = j.storeCaptures(rule, value); err != nil {
+		return err
+	}
+
+	if rule.Store != nil {
+		stored, err := j.storeValue(rule, body)
 		if err != nil {
 			return fmt.Errorf("get value for store: %w", err)
 		}
 
-		j.storeSave(rule, string(bytes))
+		if err = j.storeSave(rule, stored); err != nil {
+			return err
+		}
 	}
 
 	switch rule.Type {
+	case rules.TypeJWT:
+		if err = j.validClaims(rule, value.(string)); err != nil {
+			if rule.Key == "" {
+				return err
+			}
+
+			return fmt.Errorf("field '%s': %w", rule.Key, err)
+		}
 	case rules.TypeObject:
+		if rule.NoAdditional != nil && *rule.NoAdditional {
+			if err = j.validNoAdditional(rule, value.([]byte)); err != nil {
+				if rule.Key == "" {
+					return err
+				}
+
+				return fmt.Errorf("field '%s': %w", rule.Key, err)
+			}
+		}
+
 		for _, subRule := range rule.Fields {
 			err = j.ValidBody(subRule, value.([]byte))
 			if err != nil {
@@ -104,6 +150,137 @@ func (j *JSON) ValidBody(rule rules.Rule, body []byte) error {
 	return nil
 }
 
+// validAbsent проверяет, что значения по ключу нет
+func (j *JSON) validAbsent(rule rules.Rule, body []byte) error {
+	var path []string
+	if rule.Key != "" {
+		path = append(path, rule.Key)
+	}
+
+	var value interface{}
+	raw, dataType, _, err := jsonparser.Get(body, path...)
+	switch {
+	case errors.Is(err, jsonparser.KeyPathNotFoundError):
+	case err != nil:
+		return fmt.Errorf("getting value of '%s': %w", rule.Key, err)
+	case dataType == jsonparser.String:
+		value = string(raw)
+	default:
+		value = raw
+	}
+
+	if err = rule.Valid(value); err != nil {
+		if rule.Key == "" {
+			return err
+		}
+
+		return fmt.Errorf("field '%s': %w", rule.Key, err)
+	}
+
+	return nil
+}
+
+// validNoAdditional проверяет, что в объекте нет полей, не описанных в fields
+func (j *JSON) validNoAdditional(rule rules.Rule, object []byte) error {
+	allowed := make(map[string]struct{}, len(rule.Fields))
+	for _, subRule := range rule.Fields {
+		key, err := j.store.Replace(subRule.Key)
+		if err != nil {
+			return fmt.Errorf("preparing rule key: %w", err)
+		}
+
+		allowed[key] = struct{}{}
+	}
+
+	var unexpected []string
+	err := jsonparser.ObjectEach(object, func(key []byte, _ []byte, _ jsonparser.ValueType, _ int) error {
+		if _, ok := allowed[string(key)]; !ok {
+			unexpected = append(unexpected, string(key))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading object fields: %w", err)
+	}
+
+	if len(unexpected) > 0 {
+		return &rules.Error{
+			Expected: rule.Expected(),
+			Actual:   string(object),
+			Err:      fmt.Errorf("unexpected fields '%s'", strings.Join(unexpected, "', '")),
+		}
+	}
+
+	return nil
+}
+
+// validPath проверяет значения, найденные по выражению JSONPath в ключе правила.
+// Правилу должно соответствовать каждое найденное значение.
+func (j *JSON) validPath(rule rules.Rule, body []byte) error {
+	path, err := jsonpath.Parse(rule.Key)
+	if err != nil {
+		return err
+	}
+
+	data, err := jsonschema.Decode(body)
+	if err != nil {
+		return fmt.Errorf("decoding body: %w", err)
+	}
+
+	matches := path.Find(data)
+	if len(matches) == 0 {
+		if err = rule.Valid(nil); err != nil {
+			return fmt.Errorf("field '%s': %w", rule.Key, err)
+		}
+
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		b, err := json.Marshal(m.Value)
+		if err != nil {
+			return fmt.Errorf("encoding '%s': %w", m.Path, err)
+		}
+
+		// Значение уже найдено, проверяем его без ключа и без сохранения
+		sub := rule
+		sub.Key = ""
+		sub.Store = nil
+
+		if err = j.ValidBody(sub, b); err != nil {
+			return fmt.Errorf("field '%s': %w", m.Path, err)
+		}
+
+		values = append(values, m.Value)
+	}
+
+	if rule.Store != nil {
+		// Одно значение сохраняется как есть, несколько - массивом
+		var stored interface{} = values
+		if len(values) == 1 {
+			stored = values[0]
+		}
+
+		b, err := json.Marshal(stored)
+		if err != nil {
+			return fmt.Errorf("get value for store: %w", err)
+		}
+
+		rule.Key = ""
+		if stored, err = j.storeValue(rule, b); err != nil {
+			return fmt.Errorf("get value for store: %w", err)
+		}
+
+		if err = j.storeSave(rule, stored); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (j *JSON) getValue(typo rules.RuleType, key string, data []byte) (interface{}, error) {
 	var (
 		value interface{}
@@ -118,15 +295,29 @@ func (j *JSON) getValue(typo rules.RuleType, key string, data []byte) (interface
 	switch typo {
 	case rules.TypeBoolean:
 		value, err = jsonparser.GetBoolean(data, path...)
-	case rules.TypeString, rules.TypeJWT, rules.TypeHEX:
+	case rules.TypeString, rules.TypeJWT, rules.TypeHEX, rules.TypeUUID, rules.TypeEmail,
+		rules.TypeURL, rules.TypeDatetime, rules.TypeIP, rules.TypeBase64:
 		value, err = jsonparser.GetString(data, path...)
-	case rules.TypeInteger:
+	case rules.TypeNull:
+		var raw []byte
+		var realType jsonparser.ValueType
+		raw, realType, _, err = jsonparser.Get(data, path...)
+
+		// Не null значение передаем в правило как есть, чтобы оно попало в отчет
+		if err == nil {
+			if realType == jsonparser.Null {
+				value = rules.NullValue{}
+			} else {
+				value = string(raw)
+			}
+		}
+	case rules.TypeInteger, rules.TypeFloat:
 		value, err = jsonparser.GetFloat(data, path...)
 	case rules.TypeObject:
 		var realType jsonparser.ValueType
 		value, realType, _, err = jsonparser.Get(data, path...)
 
-		if realType != jsonparser.Object {
+		if err == nil && realType != jsonparser.Object {
 			err = fmt.Errorf("value is not object")
 		}
 	case rules.TypeArray:
@@ -174,3 +365,52 @@ func (j *JSON) getValue(typo rules.RuleType, key string, data []byte) (interface
 
 	return value, nil
 }
+
+// storeValue возвращает значение для сохранения в хранилище с сохранением типа:
+// строки, числа, bool, объекты и массивы.
+// Если в правиле указан store-path, сохраняется вложенное значение по этому пути JSONPath,
+// например: "$.profile.id" или "$.items[0].name". Несколько найденных значений сохраняются массивом.
+func (j *JSON) storeValue(rule rules.Rule, body []byte) (interface{}, error) {
+	var path []string
+	if rule.Key != "" {
+		path = append(path, rule.Key)
+	}
+
+	bytes, dataType, _, err := jsonparser.Get(body, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch dataType {
+	case jsonparser.String:
+		value, err = jsonparser.ParseString(bytes)
+	case jsonparser.Null:
+	default:
+		value, err = jsonschema.Decode(bytes)
+	}
+
+	if err != nil || rule.StorePath == nil || *rule.StorePath == "" {
+		return value, err
+	}
+
+	storePath, err := jsonpath.Parse(*rule.StorePath)
+	if err != nil {
+		return nil, fmt.Errorf("store-path: %w", err)
+	}
+
+	matches := storePath.Find(value)
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("store-path '%s': value not found", *rule.StorePath)
+	case 1:
+		return matches[0].Value, nil
+	}
+
+	values := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		values = append(values, m.Value)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,129 @@
+package validators
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestJSONOptionalMissing(t *testing.T) {
+	varFalse := false
+	varTrue := true
+	sub := "42"
+	count := "0"
+
+	validator := NewJSONValidator(store.NewStore(nil))
+	body := []byte(`{"id": 1}`)
+
+	cases := []struct {
+		Name string
+		rule rules.Rule
+	}{
+		{
+			Name: "Необязательный jwt с проверкой claims",
+			rule: rules.Rule{
+				Type:     "jwt",
+				Key:      "token",
+				Required: &varFalse,
+				Fields:   []rules.Rule{{Key: "sub", Equal: &sub}},
+			},
+		},
+		{
+			Name: "Необязательный jwt с проверкой подписи",
+			rule: rules.Rule{
+				Type:     "jwt",
+				Key:      "token",
+				Required: &varFalse,
+				Verify:   &rules.JWTVerify{Secret: "secret"},
+				Fields:   []rules.Rule{{Key: "sub", Equal: &sub}},
+			},
+		},
+		{
+			Name: "Необязательный массив",
+			rule: rules.Rule{
+				Type:     "array",
+				Key:      "items",
+				Required: &varFalse,
+				Count:    &count,
+				Fields:   []rules.Rule{{Key: "0", Equal: &sub}},
+			},
+		},
+		{
+			Name: "Необязательный объект",
+			rule: rules.Rule{
+				Type:         "object",
+				Key:          "profile",
+				Required:     &varFalse,
+				NoAdditional: &varTrue,
+				Fields:       []rules.Rule{{Key: "name"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		assert.NotPanics(t, func() {
+			assert.Nil(t, validator.ValidBody(c.rule, body), c.Name)
+		}, c.Name)
+	}
+
+	// Обязательные значения по-прежнему должны быть в ответе
+	for _, c := range cases {
+		rule := c.rule
+		rule.Required = nil
+		assert.EqualError(t, validator.ValidBody(rule, body), fmt.Sprintf("field '%s': required not exists", rule.Key), c.Name)
+	}
+
+	// Значение другого типа - ошибка, даже если оно не обязательное
+	assert.EqualError(t, validator.ValidBody(cases[3].rule, []byte(`{"profile": 5}`)), "getting value of 'profile': value is not object")
+}
+
+func TestJSONDottedKey(t *testing.T) {
+	expected := "x"
+	validator := NewJSONValidator(store.NewStore(nil))
+
+	// Ключ без $ - имя поля, даже если в нем есть точка
+	assert.Nil(t, validator.ValidBody(rules.Rule{Type: "string", Key: "a.b", Equal: &expected}, []byte(`{"a.b": "x"}`)))
+	assert.EqualError(t, validator.ValidBody(rules.Rule{Type: "string", Key: "a.b"}, []byte(`{"a": {"b": "x"}}`)), "field 'a.b': required not exists")
+
+	// Путь начинается с $
+	assert.Nil(t, validator.ValidBody(rules.Rule{Type: "string", Key: "$.a.b", Equal: &expected}, []byte(`{"a": {"b": "x"}}`)))
+}
+
+func TestJSONStorePath(t *testing.T) {
+	body := []byte(`{"data": {"profile": {"id": 7}, "items": [{"name": "a"}, {"name": "b"}]}}`)
+
+	cases := []struct {
+		Name     string
+		path     string
+		expected string
+		err      string
+	}{
+		{Name: "Поле объекта", path: "$.profile.id", expected: "7"},
+		{Name: "Элемент массива", path: "$.items[0].name", expected: "a"},
+		{Name: "Без $", path: "items[-1].name", expected: "b"},
+		{Name: "Несколько значений", path: "$.items[*].name", expected: `["a","b"]`},
+		{Name: "Значения нет", path: "$.profile.name", err: "get value for store: store-path '$.profile.name': value not found"},
+	}
+
+	for _, c := range cases {
+		s := store.NewStore(nil)
+		name := "value"
+		path := c.path
+
+		err := NewJSONValidator(s).ValidBody(rules.Rule{Type: "object", Key: "data", Store: &name, StorePath: &path}, body)
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, c.Name)
+			continue
+		}
+
+		assert.Nil(t, err, c.Name)
+
+		actual, err := s.Replace("{{.value}}")
+		assert.Nil(t, err, c.Name)
+		assert.Equal(t, c.expected, actual, c.Name)
+	}
+}
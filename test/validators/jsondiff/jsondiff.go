@@ -0,0 +1,283 @@
+// Package jsondiff сравнивает JSON значения и возвращает список отличий.
+// Значения - результат разбора JSON в interface{}, числа могут быть json.Number.
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Операции отличий, как в JSON Patch
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Change - отличие фактического значения от ожидаемого.
+// Path - JSON Pointer на значение, например /elements/0/name.
+type Change struct {
+	Op       string      `json:"op"`
+	Path     string      `json:"path"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+}
+
+// Options - параметры сравнения
+type Options struct {
+	// IgnoreOrder - массивы сравниваются без учета порядка элементов
+	IgnoreOrder bool
+	// IgnoreExtra - поля объектов, которых нет в ожидаемом значении, не считаются отличием
+	IgnoreExtra bool
+}
+
+type ignored struct{}
+
+// Ignored - значение, которое равно любому другому.
+// Им заменяются значения, которые не нужно сравнивать.
+var Ignored interface{} = ignored{}
+
+// Diff возвращает отличия actual от expected
+func Diff(expected, actual interface{}) []Change {
+	return Compare(expected, actual, Options{})
+}
+
+// Compare возвращает отличия actual от expected с учетом параметров сравнения
+func Compare(expected, actual interface{}, opts Options) []Change {
+	var changes []Change
+	diff(expected, actual, "", opts, &changes)
+
+	return changes
+}
+
+// String возвращает отличия в виде JSON для лога и отчета
+func String(changes []Change) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(changes); err != nil {
+		return err.Error()
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Format возвращает отличия текстом, по одному пути на отличие:
+// "/status: expected 2, actual 1; /name: unexpected "Иван""
+func Format(changes []Change) string {
+	parts := make([]string, 0, len(changes))
+
+	for _, c := range changes {
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+
+		switch c.Op {
+		case OpAdd:
+			parts = append(parts, fmt.Sprintf("%s: unexpected %s", path, encode(c.Actual)))
+		case OpRemove:
+			parts = append(parts, fmt.Sprintf("%s: missing %s", path, encode(c.Expected)))
+		default:
+			parts = append(parts, fmt.Sprintf("%s: expected %s, actual %s", path, encode(c.Expected), encode(c.Actual)))
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func encode(v interface{}) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func diff(expected, actual interface{}, path string, opts Options, changes *[]Change) {
+	if expected == Ignored || actual == Ignored {
+		return
+	}
+
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			*changes = append(*changes, Change{Op: OpReplace, Path: path, Expected: expected, Actual: actual})
+			return
+		}
+
+		for _, key := range sortedKeys(e, a) {
+			child := path + "/" + Escape(key)
+			ev, eok := e[key]
+			av, aok := a[key]
+
+			switch {
+			case !aok:
+				remove(ev, child, changes)
+			case !eok:
+				if !opts.IgnoreExtra {
+					add(av, child, changes)
+				}
+			default:
+				diff(ev, av, child, opts, changes)
+			}
+		}
+
+		return
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			*changes = append(*changes, Change{Op: OpReplace, Path: path, Expected: expected, Actual: actual})
+			return
+		}
+
+		if opts.IgnoreOrder {
+			diffUnordered(e, a, path, opts, changes)
+			return
+		}
+
+		for i := 0; i < len(e) || i < len(a); i++ {
+			child := path + "/" + strconv.Itoa(i)
+
+			switch {
+			case i >= len(a):
+				remove(e[i], child, changes)
+			case i >= len(e):
+				add(a[i], child, changes)
+			default:
+				diff(e[i], a[i], child, opts, changes)
+			}
+		}
+
+		return
+	}
+
+	if !Equal(expected, actual) {
+		*changes = append(*changes, Change{Op: OpReplace, Path: path, Expected: expected, Actual: actual})
+	}
+}
+
+// diffUnordered сравнивает массивы без учета порядка.
+// Для каждого ожидаемого элемента ищется равный среди фактических.
+// Оставшиеся без пары элементы сравниваются по порядку, чтобы показать отличия внутри них.
+func diffUnordered(e, a []interface{}, path string, opts Options, changes *[]Change) {
+	used := make([]bool, len(a))
+	var missing []int
+
+	for i := range e {
+		found := false
+		for j := range a {
+			if !used[j] && len(Compare(e[i], a[j], opts)) == 0 {
+				used[j] = true
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, i)
+		}
+	}
+
+	var extra []int
+	for j := range a {
+		if !used[j] {
+			extra = append(extra, j)
+		}
+	}
+
+	for k, i := range missing {
+		child := path + "/" + strconv.Itoa(i)
+
+		if k < len(extra) {
+			diff(e[i], a[extra[k]], child, opts, changes)
+		} else {
+			remove(e[i], child, changes)
+		}
+	}
+
+	for k := len(missing); k < len(extra); k++ {
+		add(a[extra[k]], path+"/"+strconv.Itoa(extra[k]), changes)
+	}
+}
+
+func add(actual interface{}, path string, changes *[]Change) {
+	if actual != Ignored {
+		*changes = append(*changes, Change{Op: OpAdd, Path: path, Actual: actual})
+	}
+}
+
+func remove(expected interface{}, path string, changes *[]Change) {
+	if expected != Ignored {
+		*changes = append(*changes, Change{Op: OpRemove, Path: path, Expected: expected})
+	}
+}
+
+// Equal сравнивает JSON значения. Числа сравниваются по значению: 1 и 1.0 равны.
+func Equal(expected, actual interface{}) bool {
+	en, eok := number(expected)
+	an, aok := number(actual)
+	if eok && aok {
+		return en == an
+	}
+
+	switch expected.(type) {
+	case map[string]interface{}, []interface{}:
+		if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+			return false
+		}
+
+		return len(Diff(expected, actual)) == 0
+	}
+
+	return reflect.DeepEqual(expected, actual)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+
+	return 0, false
+}
+
+// Escape экранирует ключ для JSON Pointer
+func Escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,50 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	var v interface{}
+	assert.Nil(t, json.Unmarshal([]byte(s), &v))
+
+	return v
+}
+
+func TestDiff(t *testing.T) {
+	expected := decode(t, `{"id":1,"name":"Ivan","tags":["a","b"],"extra":{"x":1}}`)
+	actual := decode(t, `{"tags":["a","c","d"],"name":"Ivan","id":1.0,"added":true,"extra":"x"}`)
+
+	assert.Equal(t, []Change{
+		{Op: OpAdd, Path: "/added", Actual: true},
+		{Op: OpReplace, Path: "/extra", Expected: map[string]interface{}{"x": float64(1)}, Actual: "x"},
+		{Op: OpReplace, Path: "/tags/1", Expected: "b", Actual: "c"},
+		{Op: OpAdd, Path: "/tags/2", Actual: "d"},
+	}, Diff(expected, actual))
+
+	assert.Empty(t, Diff(decode(t, `{"a":[1,{"b":null}]}`), decode(t, `{"a":[1,{"b":null}]}`)))
+	assert.Equal(t, `[{"op":"remove","path":"/a~1b","expected":1}]`, String(Diff(decode(t, `{"a/b":1}`), decode(t, `{}`))))
+}
+
+func TestCompareOptions(t *testing.T) {
+	expected := decode(t, `{"items":[{"id":1},{"id":2},{"id":3}]}`)
+	actual := decode(t, `{"items":[{"id":3},{"id":1},{"id":4}],"total":3}`)
+
+	assert.Len(t, Diff(expected, actual), 4)
+
+	changes := Compare(expected, actual, Options{IgnoreOrder: true, IgnoreExtra: true})
+	assert.Equal(t, []Change{{Op: OpReplace, Path: "/items/1/id", Expected: float64(2), Actual: float64(4)}}, changes)
+	assert.Equal(t, "/items/1/id: expected 2, actual 4", Format(changes))
+
+	assert.Equal(t, `/total: unexpected 3; /a: missing "<b>"`, Format([]Change{
+		{Op: OpAdd, Path: "/total", Actual: float64(3)},
+		{Op: OpRemove, Path: "/a", Expected: "<b>"},
+	}))
+
+	ignored := map[string]interface{}{"id": Ignored, "name": "a"}
+	assert.Empty(t, Diff(ignored, map[string]interface{}{"name": "a"}))
+	assert.Empty(t, Diff(ignored, map[string]interface{}{"id": 5, "name": "a"}))
+}
@@ -0,0 +1,262 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// expr - выражение фильтра, вычисляется для каждого элемента
+type expr interface {
+	eval(current interface{}) interface{}
+}
+
+// missing - значение пути, которого нет в элементе
+type missing struct{}
+
+type literal struct {
+	value interface{}
+}
+
+func (l literal) eval(interface{}) interface{} {
+	return l.value
+}
+
+// relative - путь от текущего элемента: @.name
+type relative struct {
+	segments []segment
+}
+
+func (r relative) eval(current interface{}) interface{} {
+	matches := find(r.segments, []Match{{Path: "@", Value: current}})
+	if len(matches) == 0 {
+		return missing{}
+	}
+
+	return matches[0].Value
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+func (b binary) eval(current interface{}) interface{} {
+	switch b.op {
+	case "&&":
+		return truthy(b.left.eval(current)) && truthy(b.right.eval(current))
+	case "||":
+		return truthy(b.left.eval(current)) || truthy(b.right.eval(current))
+	}
+
+	return compare(b.op, b.left.eval(current), b.right.eval(current))
+}
+
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case missing:
+		return false
+	case bool:
+		return v
+	case nil:
+		return false
+	}
+
+	return true
+}
+
+func compare(op string, left, right interface{}) bool {
+	if _, ok := left.(missing); ok {
+		return false
+	}
+	if _, ok := right.(missing); ok {
+		return false
+	}
+
+	lf, lok := number(left)
+	rf, rok := number(right)
+	if lok && rok {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+
+	ls, lok := left.(string)
+	rs, rok := right.(string)
+	if lok && rok {
+		switch op {
+		case "==":
+			return ls == rs
+		case "!=":
+			return ls != rs
+		case "<":
+			return ls < rs
+		case "<=":
+			return ls <= rs
+		case ">":
+			return ls > rs
+		case ">=":
+			return ls >= rs
+		}
+	}
+
+	switch op {
+	case "==":
+		return fmt.Sprint(left) == fmt.Sprint(right) && kind(left) == kind(right)
+	case "!=":
+		return fmt.Sprint(left) != fmt.Sprint(right) || kind(left) != kind(right)
+	}
+
+	return false
+}
+
+func kind(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
+
+func number(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// or разбирает выражение фильтра: операнды, сравнения, && и ||
+func (p *parser) or() (expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpaces()
+		if !strings.HasPrefix(p.src[p.pos:], "||") {
+			return left, nil
+		}
+		p.pos += 2
+
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "||", left: left, right: right}
+	}
+}
+
+func (p *parser) and() (expr, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpaces()
+		if !strings.HasPrefix(p.src[p.pos:], "&&") {
+			return left, nil
+		}
+		p.pos += 2
+
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *parser) comparison() (expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.src[p.pos:], op) {
+			p.pos += len(op)
+
+			right, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+
+			return binary{op: op, left: left, right: right}, nil
+		}
+	}
+
+	return left, nil
+}
+
+func (p *parser) operand() (expr, error) {
+	p.skipSpaces()
+
+	switch c := p.peek(); {
+	case c == '@':
+		p.pos++
+		segments, err := p.path(false)
+		if err != nil {
+			return nil, err
+		}
+		return relative{segments: segments}, nil
+	case c == '(':
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+		p.pos++
+		return e, nil
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return nil, err
+		}
+		return literal{value: s}, nil
+	}
+
+	word := p.name()
+	switch word {
+	case "true":
+		return literal{value: true}, nil
+	case "false":
+		return literal{value: false}, nil
+	case "null":
+		return literal{value: nil}, nil
+	case "":
+		return nil, fmt.Errorf("expected operand at %d", p.pos)
+	}
+
+	// name останавливается на точке, дочитываем дробную часть числа
+	if p.peek() == '.' {
+		p.pos++
+		word += "." + p.name()
+	}
+
+	f, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal '%s'", word)
+	}
+
+	return literal{value: f}, nil
+}
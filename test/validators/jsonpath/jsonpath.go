@@ -0,0 +1,213 @@
+// Package jsonpath реализует подмножество JSONPath для поиска значений в JSON.
+//
+// Поддерживается:
+//   - $ - корень документа, можно не указывать: data.items[0] и $.data.items[0] равнозначны.
+//     В ключе правила путем считается только выражение, которое начинается с $
+//   - .name и ['name'] - поле объекта
+//   - [0], [-1] - элемент массива, отрицательный индекс считается с конца
+//   - .* и [*] - все элементы массива или поля объекта
+//   - ..name - рекурсивный спуск
+//   - [?(@.name=='Иван' && @.age > 18)] - фильтр по содержимому элементов
+package jsonpath
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Match - найденное значение и конкретный путь к нему, например $.data.items[1].name
+type Match struct {
+	Path  string
+	Value interface{}
+}
+
+// Path - разобранное выражение JSONPath
+type Path struct {
+	segments []segment
+}
+
+type segmentKind int
+
+const (
+	kindName segmentKind = iota
+	kindIndex
+	kindWildcard
+	kindFilter
+)
+
+type segment struct {
+	kind      segmentKind
+	name      string
+	index     int
+	filter    expr
+	recursive bool
+}
+
+// IsPath проверяет, является ли ключ выражением пути, а не именем поля.
+// Путь начинается с $, остальные ключи - имена полей, даже если содержат точку: "a.b"
+func IsPath(key string) bool {
+	return strings.HasPrefix(key, "$")
+}
+
+// Parse разбирает выражение JSONPath
+func Parse(path string) (*Path, error) {
+	p := &parser{src: path}
+
+	segments, err := p.path(true)
+	if err != nil {
+		return nil, fmt.Errorf("parsing path '%s': %w", path, err)
+	}
+
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("parsing path '%s': unexpected '%c' at %d", path, p.src[p.pos], p.pos)
+	}
+
+	return &Path{segments: segments}, nil
+}
+
+// Find возвращает все значения документа, подходящие под путь.
+// Документ - результат json.Unmarshal в interface{}.
+func (p *Path) Find(data interface{}) []Match {
+	return find(p.segments, []Match{{Path: "$", Value: data}})
+}
+
+// Replace заменяет все найденные по пути значения документа на value.
+// Корень документа не заменяется. Возвращает количество замен.
+func (p *Path) Replace(data interface{}, value interface{}) int {
+	replaced := 0
+
+	for _, m := range p.Find(data) {
+		concrete, err := Parse(m.Path)
+		if err != nil || len(concrete.segments) == 0 {
+			continue
+		}
+
+		last := len(concrete.segments) - 1
+		parents := find(concrete.segments[:last], []Match{{Path: "$", Value: data}})
+		if len(parents) != 1 {
+			continue
+		}
+
+		seg := concrete.segments[last]
+		switch parent := parents[0].Value.(type) {
+		case map[string]interface{}:
+			if seg.kind == kindName {
+				parent[seg.name] = value
+				replaced++
+			}
+		case []interface{}:
+			if seg.kind == kindIndex {
+				parent[seg.index] = value
+				replaced++
+			}
+		}
+	}
+
+	return replaced
+}
+
+func find(segments []segment, matches []Match) []Match {
+	for _, seg := range segments {
+		var next []Match
+
+		for _, m := range matches {
+			if seg.recursive {
+				for _, d := range descendants(m) {
+					next = append(next, seg.apply(d)...)
+				}
+				continue
+			}
+
+			next = append(next, seg.apply(m)...)
+		}
+
+		matches = next
+	}
+
+	return matches
+}
+
+// apply выбирает дочерние значения по сегменту пути
+func (s segment) apply(m Match) []Match {
+	switch s.kind {
+	case kindName:
+		if obj, ok := m.Value.(map[string]interface{}); ok {
+			if v, ok := obj[s.name]; ok {
+				return []Match{{Path: childPath(m.Path, s.name), Value: v}}
+			}
+		}
+	case kindIndex:
+		if arr, ok := m.Value.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []Match{{Path: indexPath(m.Path, i), Value: arr[i]}}
+			}
+		}
+	case kindWildcard:
+		return children(m)
+	case kindFilter:
+		var res []Match
+		for _, c := range children(m) {
+			if truthy(s.filter.eval(c.Value)) {
+				res = append(res, c)
+			}
+		}
+		return res
+	}
+
+	return nil
+}
+
+// children возвращает элементы массива или поля объекта по алфавиту
+func children(m Match) []Match {
+	var res []Match
+
+	switch v := m.Value.(type) {
+	case []interface{}:
+		for i, item := range v {
+			res = append(res, Match{Path: indexPath(m.Path, i), Value: item})
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			res = append(res, Match{Path: childPath(m.Path, k), Value: v[k]})
+		}
+	}
+
+	return res
+}
+
+// descendants возвращает само значение и все вложенные в него значения
+func descendants(m Match) []Match {
+	res := []Match{m}
+	for _, c := range children(m) {
+		res = append(res, descendants(c)...)
+	}
+
+	return res
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func childPath(parent, name string) string {
+	if identRe.MatchString(name) {
+		return parent + "." + name
+	}
+
+	return parent + "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
+
+func indexPath(parent string, i int) string {
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
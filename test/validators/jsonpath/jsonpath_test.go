@@ -0,0 +1,95 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `{
+  "data": {
+    "accounts": [
+      {"name": "Ivan", "email": "ivan@mail.com", "age": 30},
+      {"name": "Roman", "email": "roman@mail.com", "age": 17}
+    ],
+    "reports": [
+      {"name": "Заправки", "price": 10.5},
+      {"name": "Магазины", "price": 3}
+    ],
+    "odd key": true
+  }
+}`
+
+func TestFind(t *testing.T) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(document)))
+	dec.UseNumber()
+
+	var data interface{}
+	assert.Nil(t, dec.Decode(&data))
+
+	cases := []struct {
+		Path   string
+		Expect []string
+	}{
+		{Path: "data.accounts[1].name", Expect: []string{"$.data.accounts[1].name"}},
+		{Path: "$.data.accounts[-1].name", Expect: []string{"$.data.accounts[1].name"}},
+		{Path: "data.accounts[*].email", Expect: []string{"$.data.accounts[0].email", "$.data.accounts[1].email"}},
+		{Path: "$..price", Expect: []string{"$.data.reports[0].price", "$.data.reports[1].price"}},
+		{Path: "$.data['odd key']", Expect: []string{"$.data['odd key']"}},
+		{Path: "data.reports[?(@.name=='Заправки')].price", Expect: []string{"$.data.reports[0].price"}},
+		{Path: "data.accounts[?(@.age >= 18 && @.name != 'Roman')].name", Expect: []string{"$.data.accounts[0].name"}},
+		{Path: "data.reports[?(@.price < 5 || @.name == 'none')]", Expect: []string{"$.data.reports[1]"}},
+		{Path: "data.accounts[?(@.phone)]", Expect: nil},
+		{Path: "data.unknown[0]", Expect: nil},
+	}
+
+	for _, curCase := range cases {
+		path, err := Parse(curCase.Path)
+		if !assert.Nil(t, err, curCase.Path) {
+			continue
+		}
+
+		var paths []string
+		for _, m := range path.Find(data) {
+			paths = append(paths, m.Path)
+		}
+
+		assert.Equal(t, curCase.Expect, paths, curCase.Path)
+	}
+
+	path, err := Parse("data.accounts[1].name")
+	assert.Nil(t, err)
+	assert.Equal(t, "Roman", path.Find(data)[0].Value)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, p := range []string{"data[", "data[?(@.a == )]", "data['a]", "data[x]"} {
+		_, err := Parse(p)
+		assert.NotNil(t, err, p)
+	}
+}
+
+func TestIsPath(t *testing.T) {
+	assert.False(t, IsPath("success"))
+	assert.False(t, IsPath("data.success"))
+	assert.False(t, IsPath("items[0]"))
+	assert.True(t, IsPath("$.data.success"))
+	assert.True(t, IsPath("$..price"))
+}
+
+func TestReplace(t *testing.T) {
+	var data interface{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"id":"a1","items":[{"id":1,"name":"x"},{"id":2,"name":"y"}],"meta":{"created at":"now"}}`), &data))
+
+	for _, p := range []string{"id", "items[*].id", "$.meta['created at']"} {
+		path, err := Parse(p)
+		assert.Nil(t, err)
+		assert.NotZero(t, path.Replace(data, "-"), p)
+	}
+
+	b, err := json.Marshal(data)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id":"-","items":[{"id":"-","name":"x"},{"id":"-","name":"y"}],"meta":{"created at":"-"}}`, string(b))
+}
@@ -0,0 +1,182 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+
+	return 0
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// path разбирает сегменты пути. root - путь от корня документа,
+// для него первое поле можно указать без точки: data.items
+func (p *parser) path(root bool) ([]segment, error) {
+	var segments []segment
+
+	if root {
+		if p.peek() == '$' {
+			p.pos++
+		} else if p.peek() != '.' && p.peek() != '[' {
+			name := p.name()
+			if name == "" {
+				return nil, fmt.Errorf("empty path")
+			}
+			segments = append(segments, segment{kind: kindName, name: name})
+		}
+	}
+
+	for p.pos < len(p.src) {
+		switch p.peek() {
+		case '.':
+			p.pos++
+			recursive := false
+			if p.peek() == '.' {
+				p.pos++
+				recursive = true
+			}
+
+			switch {
+			case p.peek() == '*':
+				p.pos++
+				segments = append(segments, segment{kind: kindWildcard, recursive: recursive})
+			case p.peek() == '[' && recursive:
+				seg, err := p.bracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.recursive = true
+				segments = append(segments, seg)
+			default:
+				name := p.name()
+				if name == "" {
+					return nil, fmt.Errorf("expected field name at %d", p.pos)
+				}
+				segments = append(segments, segment{kind: kindName, name: name, recursive: recursive})
+			}
+		case '[':
+			seg, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+		default:
+			return segments, nil
+		}
+	}
+
+	return segments, nil
+}
+
+// name читает имя поля до следующего сегмента или оператора
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(".[]()=!<>&|' ", rune(p.src[p.pos])) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// bracket разбирает сегмент в квадратных скобках: [0], [*], ['name'], [?(...)]
+func (p *parser) bracket() (segment, error) {
+	p.pos++ // [
+	p.skipSpaces()
+
+	var seg segment
+
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		seg = segment{kind: kindWildcard}
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		if err != nil {
+			return seg, err
+		}
+		seg = segment{kind: kindName, name: s}
+	case c == '?':
+		p.pos++
+		p.skipSpaces()
+		if p.peek() != '(' {
+			return seg, fmt.Errorf("expected '(' after '?' at %d", p.pos)
+		}
+		p.pos++
+
+		e, err := p.or()
+		if err != nil {
+			return seg, err
+		}
+
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return seg, fmt.Errorf("expected ')' at %d", p.pos)
+		}
+		p.pos++
+		seg = segment{kind: kindFilter, filter: e}
+	default:
+		start := p.pos
+		if c == '-' {
+			p.pos++
+		}
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+
+		index, err := strconv.Atoi(p.src[start:p.pos])
+		if err != nil {
+			return seg, fmt.Errorf("invalid index at %d", start)
+		}
+		seg = segment{kind: kindIndex, index: index}
+	}
+
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return seg, fmt.Errorf("expected ']' at %d", p.pos)
+	}
+	p.pos++
+
+	return seg, nil
+}
+
+// quoted читает строку в одинарных или двойных кавычках
+func (p *parser) quoted() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+
+		switch c {
+		case '\\':
+			if p.pos < len(p.src) {
+				b.WriteByte(p.src[p.pos])
+				p.pos++
+			}
+		case quote:
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("unterminated string")
+}
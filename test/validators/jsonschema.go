@@ -0,0 +1,127 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonpath"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type JSONSchema struct {
+	StoreBase
+	schema *jsonschema.Schema
+}
+
+// NewJSONSchemaValidator возвращает валидатор по JSON Schema.
+// Схема берется из файла schema-file или описывается в yaml в поле schema.
+func NewJSONSchemaValidator(store *store.Store, validator ValidatorDescr) (*JSONSchema, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case validator.SchemaFile != "" && validator.Schema != nil:
+		return nil, fmt.Errorf("both schema and schema-file are specified")
+	case validator.SchemaFile != "":
+		data, err = ioutil.ReadFile(validator.SchemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading schema file: %w", err)
+		}
+	case validator.Schema != nil:
+		data, err = json.Marshal(validator.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("encoding schema: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("schema or schema-file is required")
+	}
+
+	schema, err := jsonschema.Compile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONSchema{
+		StoreBase: StoreBase{store: store},
+		schema:    schema,
+	}, nil
+}
+
+// ValidBody проверяет схемой тело целиком или значение по ключу правила.
+// Ключ может быть путем JSONPath, тогда схемой проверяется каждое найденное значение.
+func (j *JSONSchema) ValidBody(rule rules.Rule, body []byte) error {
+	var err error
+	rule, err = j.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := jsonschema.Decode(body)
+	if err != nil {
+		return fmt.Errorf("decoding body: %w", err)
+	}
+
+	if rule.Key == "" {
+		if err = j.schema.Validate(data); err != nil {
+			return err
+		}
+
+		return j.storeSave(rule, data)
+	}
+
+	matches, err := schemaMatches(rule.Key, data)
+	if err != nil {
+		return err
+	}
+
+	if len(matches) == 0 {
+		if err = rule.Valid(nil); err != nil {
+			return fmt.Errorf("field '%s': %w", rule.Key, err)
+		}
+
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		if err = j.schema.Validate(m.Value); err != nil {
+			return fmt.Errorf("field '%s': %w", m.Path, err)
+		}
+
+		values = append(values, m.Value)
+	}
+
+	// Как и в валидаторе json: одно значение сохраняется как есть, несколько - массивом
+	if len(values) == 1 {
+		return j.storeSave(rule, values[0])
+	}
+
+	return j.storeSave(rule, values)
+}
+
+// schemaMatches находит значения по ключу правила.
+// Ключ, который начинается с $, - путь JSONPath, остальные ключи - имена полей.
+func schemaMatches(key string, data interface{}) ([]jsonpath.Match, error) {
+	if !jsonpath.IsPath(key) {
+		object, _ := data.(map[string]interface{})
+		value, ok := object[key]
+		if !ok {
+			return nil, nil
+		}
+
+		return []jsonpath.Match{{Path: key, Value: value}}, nil
+	}
+
+	path, err := jsonpath.Parse(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return path.Find(data), nil
+}
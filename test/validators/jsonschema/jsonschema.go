@@ -0,0 +1,120 @@
+// Package jsonschema реализует проверку JSON по подмножеству JSON Schema (draft 2020-12).
+//
+// Поддерживаются только ключевые слова, которых хватает для описания ответов API,
+// остальные ключевые слова игнорируются:
+// type, enum, const, properties, required, additionalProperties, patternProperties,
+// propertyNames, minProperties, maxProperties, dependentRequired, items, prefixItems,
+// contains, minContains, maxContains, minItems, maxItems, uniqueItems, minLength,
+// maxLength, pattern, format, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, allOf, anyOf, oneOf, not, if, then, else, $ref и $defs.
+// Ссылки $ref поддерживаются только внутри документа схемы: "#/$defs/user".
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema - разобранная схема
+type Schema struct {
+	// root - документ, относительно которого разрешаются ссылки $ref
+	root interface{}
+	// schema - схема, которой проверяются значения
+	schema interface{}
+}
+
+// Violation - нарушение схемы.
+// Pointer - JSON Pointer на проверяемое значение, например /data/items/0/id.
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+
+	return pointer + ": " + v.Message
+}
+
+// ValidationError - значение не соответствует схеме, содержит все нарушения
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	list := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		list[i] = v.String()
+	}
+
+	return "schema violations: " + strings.Join(list, "; ")
+}
+
+// Compile разбирает схему из JSON
+func Compile(data []byte) (*Schema, error) {
+	root, err := Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding schema: %w", err)
+	}
+
+	switch root.(type) {
+	case map[string]interface{}, bool:
+	default:
+		return nil, fmt.Errorf("schema must be an object or a boolean")
+	}
+
+	return &Schema{root: root, schema: root}, nil
+}
+
+// New возвращает схему, которая является частью документа root,
+// например, схему из спецификации OpenAPI. Ссылки $ref разрешаются относительно root.
+func New(root, schema interface{}) *Schema {
+	return &Schema{root: root, schema: schema}
+}
+
+// Decode разбирает JSON значение. Числа остаются json.Number без потери точности.
+// Используется всеми валидаторами, которым нужен разобранный JSON.
+func Decode(data []byte) (interface{}, error) {
+	var value interface{}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	// После значения не должно быть ничего, кроме пробелов
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return value, nil
+}
+
+// Validate проверяет значение и возвращает *ValidationError со всеми нарушениями
+func (s *Schema) Validate(instance interface{}) error {
+	v := &validator{root: s.root}
+	v.validate(s.schema, instance, "")
+
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+
+	return nil
+}
+
+// ValidateJSON разбирает JSON и проверяет его схемой
+func (s *Schema) ValidateJSON(data []byte) error {
+	instance, err := Decode(data)
+	if err != nil {
+		return fmt.Errorf("decoding json: %w", err)
+	}
+
+	return s.Validate(instance)
+}
@@ -0,0 +1,101 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const schema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "elements"],
+  "properties": {
+    "id": {"type": "string", "pattern": "^[0-9a-f]{24}$"},
+    "begin": {"type": "integer", "minimum": 0},
+    "elements": {
+      "type": "array",
+      "minItems": 1,
+      "items": {"$ref": "#/$defs/element"}
+    },
+    "showIntervals": {"type": "boolean"}
+  },
+  "additionalProperties": false,
+  "$defs": {
+    "element": {
+      "type": "object",
+      "required": ["id", "type", "name"],
+      "properties": {
+        "id": {"type": "string"},
+        "type": {"enum": [1, 2]},
+        "name": {"type": "string", "minLength": 1}
+      }
+    }
+  }
+}`
+
+func TestValid(t *testing.T) {
+	s, err := Compile([]byte(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	err = s.ValidateJSON([]byte(`{"id":"63232a0687fe52001bd6c34a","begin":1663099200000,"showIntervals":true,
+		"elements":[{"id":"a","type":1,"name":"Заправки"},{"id":"b","type":2.0,"name":"Движение ТС"}]}`))
+	assert.Nil(t, err)
+}
+
+func TestViolations(t *testing.T) {
+	s, err := Compile([]byte(schema))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	err = s.ValidateJSON([]byte(`{"id":"bad","begin":1.5,"extra":1,
+		"elements":[{"id":"a","type":3,"name":""},{"type":1,"name":"ok"}]}`))
+
+	var validationErr *ValidationError
+	if !assert.True(t, errors.As(err, &validationErr)) {
+		return
+	}
+
+	var pointers []string
+	for _, v := range validationErr.Violations {
+		pointers = append(pointers, v.String())
+	}
+
+	assert.Equal(t, []string{
+		"/begin: expected type integer, got number",
+		"/elements/0/name: string length must be at least 1, got 0",
+		"/elements/0/type: value 3 is not one of [1,2]",
+		"/elements/1: required property 'id' is missing",
+		"/extra: additional property 'extra' is not allowed",
+		"/id: string 'bad' does not match pattern '^[0-9a-f]{24}$'",
+	}, pointers)
+}
+
+func TestCombinators(t *testing.T) {
+	s, err := Compile([]byte(`{
+		"oneOf": [{"type": "string", "format": "email"}, {"type": "integer"}],
+		"not": {"const": 0}
+	}`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Nil(t, s.ValidateJSON([]byte(`"ivan@mail.com"`)))
+	assert.Nil(t, s.ValidateJSON([]byte(`5`)))
+	assert.NotNil(t, s.ValidateJSON([]byte(`"ivan"`)))
+	assert.NotNil(t, s.ValidateJSON([]byte(`0`)))
+}
+
+func TestDecode(t *testing.T) {
+	value, err := Decode([]byte(" {\"id\": 12345678901234567890}\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"id": json.Number("12345678901234567890")}, value)
+
+	_, err = Decode([]byte(`{"id": 1} {"id": 2}`))
+	assert.EqualError(t, err, "unexpected data after JSON value")
+}
@@ -0,0 +1,104 @@
+package jsonschema
+
+import (
+	"strconv"
+)
+
+// Undocumented возвращает JSON Pointer на поля значения, которые не описаны в схеме.
+// Поле считается описанным, если оно есть в properties схемы или её частей allOf, anyOf, oneOf.
+// Объекты без properties и объекты, у которых разрешены произвольные поля
+// (additionalProperties или patternProperties), не проверяются.
+func (s *Schema) Undocumented(instance interface{}) []string {
+	var pointers []string
+	s.undocumented(s.schema, instance, "", 0, &pointers)
+
+	return pointers
+}
+
+func (s *Schema) undocumented(schema, instance interface{}, pointer string, depth int, pointers *[]string) {
+	if depth >= maxDepth {
+		return
+	}
+
+	schemas := s.flatten(schema, depth)
+	if len(schemas) == 0 {
+		return
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		// Объект без описания полей считается объектом с произвольными полями
+		described := false
+		properties := make(map[string][]interface{})
+		for _, sch := range schemas {
+			if additional, ok := sch["additionalProperties"]; ok && additional != false {
+				return
+			}
+			if _, ok := sch["patternProperties"]; ok {
+				return
+			}
+
+			props, ok := sch["properties"].(map[string]interface{})
+			if ok {
+				described = true
+			}
+			for name, prop := range props {
+				properties[name] = append(properties[name], prop)
+			}
+		}
+
+		if !described {
+			return
+		}
+
+		for _, key := range sortedKeys(value) {
+			child := pointer + "/" + escapePointer(key)
+
+			props, ok := properties[key]
+			if !ok {
+				*pointers = append(*pointers, child)
+				continue
+			}
+
+			for _, prop := range props {
+				s.undocumented(prop, value[key], child, depth+1, pointers)
+			}
+		}
+	case []interface{}:
+		for _, sch := range schemas {
+			items, ok := sch["items"]
+			if !ok {
+				continue
+			}
+
+			for i, item := range value {
+				s.undocumented(items, item, pointer+"/"+strconv.Itoa(i), depth+1, pointers)
+			}
+		}
+	}
+}
+
+// flatten возвращает схему и все схемы, из которых она составлена через $ref, allOf, anyOf и oneOf
+func (s *Schema) flatten(schema interface{}, depth int) []map[string]interface{} {
+	sch, ok := schema.(map[string]interface{})
+	if !ok || depth >= maxDepth {
+		return nil
+	}
+
+	list := []map[string]interface{}{sch}
+
+	if ref, ok := sch["$ref"].(string); ok {
+		if target, err := Resolve(s.root, ref); err == nil {
+			list = append(list, s.flatten(target, depth+1)...)
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		parts, _ := sch[keyword].([]interface{})
+		for _, part := range parts {
+			list = append(list, s.flatten(part, depth+1)...)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,565 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxDepth ограничивает глубину $ref, чтобы рекурсивная схема не зациклилась
+const maxDepth = 64
+
+type validator struct {
+	root       interface{}
+	violations []Violation
+	depth      int
+}
+
+func (v *validator) add(pointer, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// valid проверяет значение отдельно, не добавляя нарушения.
+// Используется для anyOf, oneOf, not, if и contains.
+func (v *validator) valid(schema, instance interface{}, pointer string) bool {
+	sub := &validator{root: v.root, depth: v.depth}
+	sub.validate(schema, instance, pointer)
+
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(schema, instance interface{}, pointer string) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.add(pointer, "value is not allowed")
+		}
+		return
+	case map[string]interface{}:
+		v.validateObject(s, instance, pointer)
+	default:
+		v.add(pointer, "invalid schema")
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, instance interface{}, pointer string) {
+	if ref, ok := s["$ref"].(string); ok {
+		v.ref(ref, instance, pointer)
+	}
+
+	if t, ok := s["type"]; ok {
+		v.checkType(t, instance, pointer)
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(pointer, "value %s is not one of %s", encode(instance), encode(enum))
+		}
+	}
+
+	if c, ok := s["const"]; ok && !equal(c, instance) {
+		v.add(pointer, "value %s must be %s", encode(instance), encode(c))
+	}
+
+	v.combinators(s, instance, pointer)
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		v.objectKeywords(s, value, pointer)
+	case []interface{}:
+		v.arrayKeywords(s, value, pointer)
+	case string:
+		v.stringKeywords(s, value, pointer)
+	case json.Number:
+		v.numberKeywords(s, value, pointer)
+	}
+}
+
+func (v *validator) ref(ref string, instance interface{}, pointer string) {
+	if v.depth >= maxDepth {
+		v.add(pointer, "too deep $ref '%s'", ref)
+		return
+	}
+
+	target, err := Resolve(v.root, ref)
+	if err != nil {
+		v.add(pointer, "%s", err)
+		return
+	}
+
+	v.depth++
+	v.validate(target, instance, pointer)
+	v.depth--
+}
+
+// Resolve находит значение в документе по ссылке вида #/$defs/user
+func Resolve(root interface{}, ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref '%s': only local references are supported", ref)
+	}
+
+	node := root
+	path := strings.TrimPrefix(ref, "#")
+	if path == "" {
+		return node, nil
+	}
+
+	for _, part := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			part = unescaped
+		}
+
+		switch n := node.(type) {
+		case map[string]interface{}:
+			next, ok := n[part]
+			if !ok {
+				return nil, fmt.Errorf("$ref '%s' not found", ref)
+			}
+			node = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("$ref '%s' not found", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("$ref '%s' not found", ref)
+		}
+	}
+
+	return node, nil
+}
+
+func (v *validator) checkType(t interface{}, instance interface{}, pointer string) {
+	var types []string
+	switch t := t.(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	actual := typeOf(instance)
+	for _, expected := range types {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return
+		}
+	}
+
+	v.add(pointer, "expected type %s, got %s", strings.Join(types, " or "), actual)
+}
+
+func (v *validator) combinators(s map[string]interface{}, instance interface{}, pointer string) {
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, instance, pointer)
+		}
+	}
+
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, sub := range anyOf {
+			if v.valid(sub, instance, pointer) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.add(pointer, "value does not match any schema of anyOf")
+		}
+	}
+
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		count := 0
+		for _, sub := range oneOf {
+			if v.valid(sub, instance, pointer) {
+				count++
+			}
+		}
+		if count != 1 {
+			v.add(pointer, "value must match exactly one schema of oneOf, matched %d", count)
+		}
+	}
+
+	if not, ok := s["not"]; ok && v.valid(not, instance, pointer) {
+		v.add(pointer, "value must not match schema of not")
+	}
+
+	if cond, ok := s["if"]; ok {
+		if v.valid(cond, instance, pointer) {
+			if then, ok := s["then"]; ok {
+				v.validate(then, instance, pointer)
+			}
+		} else if els, ok := s["else"]; ok {
+			v.validate(els, instance, pointer)
+		}
+	}
+}
+
+func (v *validator) objectKeywords(s map[string]interface{}, obj map[string]interface{}, pointer string) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, exists := obj[key]; !exists {
+					v.add(pointer, "required property '%s' is missing", key)
+				}
+			}
+		}
+	}
+
+	if deps, ok := s["dependentRequired"].(map[string]interface{}); ok {
+		for key, list := range deps {
+			if _, exists := obj[key]; !exists {
+				continue
+			}
+			names, _ := list.([]interface{})
+			for _, name := range names {
+				if dep, ok := name.(string); ok {
+					if _, exists := obj[dep]; !exists {
+						v.add(pointer, "property '%s' is required by '%s'", dep, key)
+					}
+				}
+			}
+		}
+	}
+
+	if n, ok := intKeyword(s, "minProperties"); ok && len(obj) < n {
+		v.add(pointer, "object must have at least %d properties, has %d", n, len(obj))
+	}
+	if n, ok := intKeyword(s, "maxProperties"); ok && len(obj) > n {
+		v.add(pointer, "object must have at most %d properties, has %d", n, len(obj))
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patterns, _ := s["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := s["additionalProperties"]
+	names, hasNames := s["propertyNames"]
+
+	for _, key := range sortedKeys(obj) {
+		value := obj[key]
+		child := pointer + "/" + escapePointer(key)
+
+		if hasNames {
+			v.validate(names, key, child)
+		}
+
+		matched := false
+		if sub, ok := properties[key]; ok {
+			matched = true
+			v.validate(sub, value, child)
+		}
+
+		for pattern, sub := range patterns {
+			re, err := compileRegexp(pattern)
+			if err != nil {
+				v.add(pointer, "invalid patternProperties '%s': %s", pattern, err)
+				continue
+			}
+			if re.MatchString(key) {
+				matched = true
+				v.validate(sub, value, child)
+			}
+		}
+
+		if !matched && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				v.add(child, "additional property '%s' is not allowed", key)
+				continue
+			}
+			v.validate(additional, value, child)
+		}
+	}
+}
+
+func (v *validator) arrayKeywords(s map[string]interface{}, arr []interface{}, pointer string) {
+	if n, ok := intKeyword(s, "minItems"); ok && len(arr) < n {
+		v.add(pointer, "array must have at least %d items, has %d", n, len(arr))
+	}
+	if n, ok := intKeyword(s, "maxItems"); ok && len(arr) > n {
+		v.add(pointer, "array must have at most %d items, has %d", n, len(arr))
+	}
+
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+		for i := 0; i < len(arr); i++ {
+			for j := i + 1; j < len(arr); j++ {
+				if equal(arr[i], arr[j]) {
+					v.add(pointer, "items %d and %d are equal, but must be unique", i, j)
+				}
+			}
+		}
+	}
+
+	prefix, _ := s["prefixItems"].([]interface{})
+	for i, sub := range prefix {
+		if i < len(arr) {
+			v.validate(sub, arr[i], pointer+"/"+strconv.Itoa(i))
+		}
+	}
+
+	if items, ok := s["items"]; ok {
+		for i := len(prefix); i < len(arr); i++ {
+			v.validate(items, arr[i], pointer+"/"+strconv.Itoa(i))
+		}
+	}
+
+	if contains, ok := s["contains"]; ok {
+		count := 0
+		for i, item := range arr {
+			if v.valid(contains, item, pointer+"/"+strconv.Itoa(i)) {
+				count++
+			}
+		}
+
+		min, hasMin := intKeyword(s, "minContains")
+		if !hasMin {
+			min = 1
+		}
+		if count < min {
+			v.add(pointer, "array must contain at least %d matching items, contains %d", min, count)
+		}
+		if max, ok := intKeyword(s, "maxContains"); ok && count > max {
+			v.add(pointer, "array must contain at most %d matching items, contains %d", max, count)
+		}
+	}
+}
+
+func (v *validator) stringKeywords(s map[string]interface{}, str string, pointer string) {
+	length := utf8.RuneCountInString(str)
+
+	if n, ok := intKeyword(s, "minLength"); ok && length < n {
+		v.add(pointer, "string length must be at least %d, got %d", n, length)
+	}
+	if n, ok := intKeyword(s, "maxLength"); ok && length > n {
+		v.add(pointer, "string length must be at most %d, got %d", n, length)
+	}
+
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := compileRegexp(pattern)
+		if err != nil {
+			v.add(pointer, "invalid pattern '%s': %s", pattern, err)
+		} else if !re.MatchString(str) {
+			v.add(pointer, "string '%s' does not match pattern '%s'", str, pattern)
+		}
+	}
+
+	if format, ok := s["format"].(string); ok {
+		if err := checkFormat(format, str); err != nil {
+			v.add(pointer, "string '%s' is not valid %s: %s", str, format, err)
+		}
+	}
+}
+
+func (v *validator) numberKeywords(s map[string]interface{}, num json.Number, pointer string) {
+	value, err := num.Float64()
+	if err != nil {
+		v.add(pointer, "invalid number %s", num)
+		return
+	}
+
+	if min, ok := floatKeyword(s, "minimum"); ok && value < min {
+		v.add(pointer, "value %s must be >= %s", num, formatFloat(min))
+	}
+	if max, ok := floatKeyword(s, "maximum"); ok && value > max {
+		v.add(pointer, "value %s must be <= %s", num, formatFloat(max))
+	}
+	if min, ok := floatKeyword(s, "exclusiveMinimum"); ok && value <= min {
+		v.add(pointer, "value %s must be > %s", num, formatFloat(min))
+	}
+	if max, ok := floatKeyword(s, "exclusiveMaximum"); ok && value >= max {
+		v.add(pointer, "value %s must be < %s", num, formatFloat(max))
+	}
+	if div, ok := floatKeyword(s, "multipleOf"); ok && div > 0 {
+		quotient := value / div
+		if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			v.add(pointer, "value %s must be a multiple of %s", num, formatFloat(div))
+		}
+	}
+}
+
+// checkFormat проверяет распространенные форматы строк
+func checkFormat(format, str string) error {
+	var err error
+
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, str)
+	case "date":
+		_, err = time.Parse("2006-01-02", str)
+	case "time":
+		_, err = time.Parse("15:04:05Z07:00", str)
+	case "email":
+		_, err = mail.ParseAddress(str)
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(str)
+		if err == nil && u.Scheme == "" {
+			err = fmt.Errorf("missing scheme")
+		}
+	case "uuid":
+		if !uuidRe.MatchString(str) {
+			err = fmt.Errorf("invalid uuid")
+		}
+	case "ipv4":
+		if ip := net.ParseIP(str); ip == nil || ip.To4() == nil {
+			err = fmt.Errorf("invalid ipv4 address")
+		}
+	case "ipv6":
+		if ip := net.ParseIP(str); ip == nil || ip.To4() != nil {
+			err = fmt.Errorf("invalid ipv6 address")
+		}
+	case "regex":
+		_, err = regexp.Compile(str)
+	}
+
+	// Неизвестные форматы не проверяются
+	return err
+}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// regexpCache - скомпилированные pattern, группы выполняются параллельно
+var regexpCache sync.Map
+
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexpCache.Store(pattern, re)
+
+	return re, nil
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+// equal сравнивает JSON значения, числа сравниваются по значению: 1 и 1.0 равны
+func equal(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	switch av := a.(type) {
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, item := range av {
+			other, exists := bv[k]
+			if !exists || !equal(item, other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func intKeyword(s map[string]interface{}, name string) (int, bool) {
+	f, ok := floatKeyword(s, name)
+	return int(f), ok
+}
+
+func floatKeyword(s map[string]interface{}, name string) (float64, bool) {
+	n, ok := s[name].(json.Number)
+	if !ok {
+		return 0, false
+	}
+
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func encode(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(b)
+}
+
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
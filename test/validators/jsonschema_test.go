@@ -0,0 +1,53 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestJSONSchemaKey(t *testing.T) {
+	body := []byte(`{"a.b": 1, "items": [{"id": 1}, {"id": 2}], "profile": {"id": 3}}`)
+	varFalse := false
+
+	cases := []struct {
+		Name   string
+		rule   rules.Rule
+		expect string
+		err    string
+	}{
+		{Name: "Поле с точкой в имени", rule: rules.Rule{Key: "a.b"}, expect: "1"},
+		{Name: "Одно значение по пути", rule: rules.Rule{Key: "$.profile.id"}, expect: "3"},
+		{Name: "Несколько значений по пути", rule: rules.Rule{Key: "$.items[*].id"}, expect: "[1,2]"},
+		{Name: "Нет значения", rule: rules.Rule{Key: "$.user.id"}, err: "field '$.user.id': required not exists"},
+		{Name: "Нет необязательного значения", rule: rules.Rule{Key: "user", Required: &varFalse}},
+		{Name: "Значение не по схеме", rule: rules.Rule{Key: "profile"}, err: "field 'profile': schema violations: /: expected type integer, got object"},
+	}
+
+	for _, c := range cases {
+		s := store.NewStore(nil)
+		validator, err := NewJSONSchemaValidator(s, ValidatorDescr{Schema: map[string]interface{}{"type": "integer"}})
+		assert.Nil(t, err)
+
+		name := "value"
+		c.rule.Store = &name
+
+		err = validator.ValidBody(c.rule, body)
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, c.Name)
+			continue
+		}
+
+		assert.Nil(t, err, c.Name)
+		if c.expect == "" {
+			continue
+		}
+
+		actual, err := s.Replace("{{.value}}")
+		assert.Nil(t, err, c.Name)
+		assert.Equal(t, c.expect, actual, c.Name)
+	}
+}
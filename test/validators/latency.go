@@ -0,0 +1,49 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type Latency struct {
+	StoreBase
+}
+
+// NewLatencyValidator возвращает валидатор для времени выполнения запроса
+func NewLatencyValidator(store *store.Store) *Latency {
+	return &Latency{
+		StoreBase{store: store},
+	}
+}
+
+// ValidLatency проверяет замер времени запроса в миллисекундах.
+// Ключ правила - название замера (total, dns, connect, tls, ttfb), по-умолчанию total.
+func (l *Latency) ValidLatency(rule rules.Rule, timings map[string]float64) error {
+	var err error
+	rule, err = l.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	if rule.Key == "" {
+		rule.Key = "total"
+	}
+
+	value, ok := timings[rule.Key]
+	if !ok {
+		return fmt.Errorf("unknown latency key '%s'", rule.Key)
+	}
+
+	if err = l.storeSave(rule, value); err != nil {
+		return err
+	}
+
+	if err = rule.Valid(value); err != nil {
+		return fmt.Errorf("field '%s': %w", rule.Key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+)
+
+// Exchange - отправленный запрос и полученный ответ
+type Exchange struct {
+	Method          string
+	Path            string
+	RequestHeaders  http.Header
+	RequestBody     []byte
+	Status          int
+	ResponseHeaders http.Header
+	ResponseBody    []byte
+}
+
+// Violation - расхождение с контрактом.
+// Warning - расхождение не нарушает контракт явно: недокументированная операция или поле,
+// ошибки в запросе (тест мог отправить неверный запрос намеренно).
+type Violation struct {
+	Key     string
+	Err     error
+	Warning bool
+}
+
+// Check сверяет запрос и ответ со спецификацией.
+// Первым значением возвращает операцию, например "GET /users/{id}".
+func (s *Spec) Check(ex Exchange) (string, []Violation) {
+	template, op := s.operation(ex.Method, ex.Path)
+	if op == nil {
+		return "", []Violation{{
+			Key:     "operation",
+			Err:     fmt.Errorf("operation %s %s is not documented", ex.Method, ex.Path),
+			Warning: true,
+		}}
+	}
+
+	operation := strings.ToUpper(ex.Method) + " " + template
+
+	var violations []Violation
+
+	if body, ok := s.resolve(op["requestBody"]).(map[string]interface{}); ok && len(ex.RequestBody) > 0 {
+		for _, v := range s.checkContent(body, ex.RequestHeaders.Get("Content-Type"), ex.RequestBody, "request body") {
+			v.Warning = true
+			violations = append(violations, v)
+		}
+	}
+
+	response := s.response(op, ex.Status)
+	if response == nil {
+		violations = append(violations, Violation{
+			Key: "status",
+			Err: fmt.Errorf("status %d is not documented for %s", ex.Status, operation),
+		})
+
+		return operation, violations
+	}
+
+	violations = append(violations, s.checkHeaders(response, ex.ResponseHeaders)...)
+	violations = append(violations, s.checkContent(response, ex.ResponseHeaders.Get("Content-Type"), ex.ResponseBody, "body")...)
+
+	return operation, violations
+}
+
+// response находит описание ответа по коду: точный код, затем 2XX, затем default
+func (s *Spec) response(op map[string]interface{}, status int) map[string]interface{} {
+	responses, _ := op["responses"].(map[string]interface{})
+	code := strconv.Itoa(status)
+
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if resp, ok := s.resolve(responses[key]).(map[string]interface{}); ok {
+			return resp
+		}
+	}
+
+	return nil
+}
+
+func (s *Spec) checkHeaders(response map[string]interface{}, headers http.Header) []Violation {
+	var violations []Violation
+
+	described, _ := response["headers"].(map[string]interface{})
+	names := make([]string, 0, len(described))
+	for name := range described {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node := described[name]
+		// Content-Type описывается через content
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		header, ok := s.resolve(node).(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		key := "header " + name
+		values, exists := headers[http.CanonicalHeaderKey(name)]
+		if !exists {
+			if required, _ := header["required"].(bool); required {
+				violations = append(violations, Violation{Key: key, Err: fmt.Errorf("required header '%s' is missing", name)})
+			}
+			continue
+		}
+
+		schema, ok := header["schema"]
+		if !ok || len(values) == 0 {
+			continue
+		}
+
+		if err := jsonschema.New(s.root, schema).Validate(headerValue(s.resolve(schema), values[0])); err != nil {
+			violations = append(violations, Violation{Key: key, Err: fmt.Errorf("header '%s': %w", name, err)})
+		}
+	}
+
+	return violations
+}
+
+// headerValue приводит значение заголовка к типу из схемы
+func headerValue(schema interface{}, value string) interface{} {
+	sch, _ := schema.(map[string]interface{})
+
+	switch sch["type"] {
+	case "integer", "number":
+		if decoded, err := jsonschema.Decode([]byte(value)); err == nil {
+			return decoded
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+
+	return value
+}
+
+// checkContent проверяет тело по описанию content запроса или ответа
+func (s *Spec) checkContent(described map[string]interface{}, contentType string, body []byte, key string) []Violation {
+	content, _ := described["content"].(map[string]interface{})
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return []Violation{{Key: key, Err: fmt.Errorf("%s is not documented", key), Warning: true}}
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	media, ok := findMedia(content, mediaType)
+	if !ok {
+		return []Violation{{Key: key, Err: fmt.Errorf("content type '%s' is not documented", contentType)}}
+	}
+
+	schema, hasSchema := media["schema"]
+	if !hasSchema || !strings.Contains(mediaType, "json") {
+		return nil
+	}
+
+	instance, err := jsonschema.Decode(body)
+	if err != nil {
+		return []Violation{{Key: key, Err: fmt.Errorf("decoding %s: %w", key, err)}}
+	}
+
+	var violations []Violation
+
+	sch := jsonschema.New(s.root, schema)
+	if err = sch.Validate(instance); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if !errors.As(err, &validationErr) {
+			return []Violation{{Key: key, Err: err}}
+		}
+
+		for _, v := range validationErr.Violations {
+			violations = append(violations, Violation{
+				Key: key + " " + pointerOrRoot(v.Pointer),
+				Err: fmt.Errorf("%s %s", key, v),
+			})
+		}
+	}
+
+	for _, pointer := range sch.Undocumented(instance) {
+		violations = append(violations, Violation{
+			Key:     key + " " + pointer,
+			Err:     fmt.Errorf("%s field '%s' is not documented", key, pointer),
+			Warning: true,
+		})
+	}
+
+	return violations
+}
+
+// findMedia находит описание тела по типу: точное совпадение, затем application/*, затем */*
+func findMedia(content map[string]interface{}, mediaType string) (map[string]interface{}, bool) {
+	candidates := []string{mediaType}
+	if i := strings.Index(mediaType, "/"); i > 0 {
+		candidates = append(candidates, mediaType[:i]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		if media, ok := content[candidate].(map[string]interface{}); ok {
+			return media, true
+		}
+	}
+
+	return nil, false
+}
+
+func pointerOrRoot(pointer string) string {
+	if pointer == "" {
+		return "/"
+	}
+
+	return pointer
+}
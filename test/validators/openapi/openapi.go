@@ -0,0 +1,319 @@
+// Package openapi проверяет HTTP запросы и ответы по спецификации OpenAPI 3.
+//
+// Запрос сопоставляется с операцией спецификации по методу и пути,
+// затем код ответа, заголовки и тело проверяются по описанию ответа.
+// Схемы проверяются пакетом jsonschema, ссылки $ref разрешаются внутри файла спецификации.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+)
+
+// Spec - разобранная спецификация
+type Spec struct {
+	root      interface{}
+	servers   []string
+	basePaths []string
+	paths     []pathItem
+}
+
+// Operation - операция спецификации
+type Operation struct {
+	Method string
+	Path   string
+	// Spec - описание операции из спецификации
+	Spec map[string]interface{}
+}
+
+// pathItem - путь из спецификации, например /users/{id}
+type pathItem struct {
+	template string
+	re       *regexp.Regexp
+	// params - количество параметров в пути, пути без параметров имеют приоритет
+	params     int
+	operations map[string]interface{}
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = make(map[string]*Spec)
+)
+
+// Load читает спецификацию из yaml или json файла.
+// Одна спецификация обычно общая для многих групп, поэтому разобранные файлы кэшируются.
+func Load(path string) (*Spec, error) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if spec, ok := cache[path]; ok {
+		return spec, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading openapi spec: %w", err)
+	}
+
+	spec, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing openapi spec '%s': %w", path, err)
+	}
+
+	cache[path] = spec
+
+	return spec, nil
+}
+
+// Parse разбирает спецификацию из yaml или json
+func Parse(b []byte) (*Spec, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	// yaml разбирает ключи 200, 404 как числа, приводим документ к JSON
+	b, err := json.Marshal(normalize(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := jsonschema.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("spec must be an object")
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported openapi version '%s', expected 3.x", version)
+	}
+
+	convertSchemas(doc)
+
+	spec := &Spec{root: doc}
+
+	servers, _ := doc["servers"].([]interface{})
+	for _, server := range servers {
+		s, _ := server.(map[string]interface{})
+		rawURL, _ := s["url"].(string)
+		spec.servers = append(spec.servers, rawURL)
+		if u, err := url.Parse(rawURL); err == nil && !strings.Contains(rawURL, "{") {
+			if base := strings.TrimSuffix(u.Path, "/"); base != "" {
+				spec.basePaths = append(spec.basePaths, base)
+			}
+		}
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for template, item := range paths {
+		// Путь может целиком ссылаться на описание в components/pathItems
+		item, err := resolveRefs(doc, item)
+		if err != nil {
+			return nil, fmt.Errorf("path '%s': %w", template, err)
+		}
+
+		operations, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		re, params := compilePath(template)
+		spec.paths = append(spec.paths, pathItem{
+			template:   template,
+			re:         re,
+			params:     params,
+			operations: operations,
+		})
+	}
+
+	sort.Slice(spec.paths, func(i, j int) bool {
+		if spec.paths[i].params != spec.paths[j].params {
+			return spec.paths[i].params < spec.paths[j].params
+		}
+		return spec.paths[i].template < spec.paths[j].template
+	})
+
+	return spec, nil
+}
+
+var paramRe = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// compilePath превращает шаблон пути /users/{id} в регулярное выражение
+func compilePath(template string) (*regexp.Regexp, int) {
+	parts := paramRe.Split(template, -1)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	return regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "/?$"), len(parts) - 1
+}
+
+var methods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Operations возвращает все операции спецификации, отсортированные по пути и методу
+func (s *Spec) Operations() []Operation {
+	var list []Operation
+
+	for _, item := range s.paths {
+		for _, method := range methods {
+			if op, ok := s.resolve(item.operations[method]).(map[string]interface{}); ok {
+				list = append(list, Operation{Method: strings.ToUpper(method), Path: item.template, Spec: op})
+			}
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Path < list[j].Path
+	})
+
+	return list
+}
+
+// Servers возвращает адреса серверов из спецификации
+func (s *Spec) Servers() []string {
+	return s.servers
+}
+
+// Document возвращает документ спецификации
+func (s *Spec) Document() map[string]interface{} {
+	doc, _ := s.root.(map[string]interface{})
+	return doc
+}
+
+// Resolve возвращает объект, на который ссылается $ref, или сам объект
+func (s *Spec) Resolve(node interface{}) interface{} {
+	return s.resolve(node)
+}
+
+// operation находит операцию по методу и пути запроса
+func (s *Spec) operation(method, path string) (string, map[string]interface{}) {
+	candidates := []string{path}
+	for _, base := range s.basePaths {
+		if strings.HasPrefix(path, base) {
+			candidates = append(candidates, strings.TrimPrefix(path, base))
+		}
+	}
+
+	method = strings.ToLower(method)
+	for _, candidate := range candidates {
+		for _, item := range s.paths {
+			if !item.re.MatchString(candidate) {
+				continue
+			}
+
+			if op, ok := s.resolve(item.operations[method]).(map[string]interface{}); ok {
+				return item.template, op
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// resolve возвращает объект, на который ссылается $ref, или сам объект.
+// Если ссылка не найдена, возвращает nil.
+func (s *Spec) resolve(node interface{}) interface{} {
+	node, err := resolveRefs(s.root, node)
+	if err != nil {
+		return nil
+	}
+
+	return node
+}
+
+// resolveRefs проходит по цепочке ссылок $ref, пока не дойдет до объекта без ссылки
+func resolveRefs(root, node interface{}) (interface{}, error) {
+	for i := 0; i < 16; i++ {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return node, nil
+		}
+
+		ref, ok := obj["$ref"].(string)
+		if !ok {
+			return node, nil
+		}
+
+		target, err := jsonschema.Resolve(root, ref)
+		if err != nil {
+			return nil, err
+		}
+		node = target
+	}
+
+	return nil, fmt.Errorf("too many nested $ref")
+}
+
+// normalize приводит ключи объектов yaml к строкам
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = normalize(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	}
+
+	return value
+}
+
+// convertSchemas приводит схемы OpenAPI 3.0 к JSON Schema:
+// nullable превращается в тип null, булевые exclusiveMinimum и exclusiveMaximum - в числа
+func convertSchemas(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if nullable, ok := v["nullable"].(bool); ok && nullable {
+			if t, ok := v["type"].(string); ok {
+				v["type"] = []interface{}{t, "null"}
+			}
+			delete(v, "nullable")
+		}
+
+		for _, pair := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+			exclusive, ok := v[pair[0]].(bool)
+			if !ok {
+				continue
+			}
+
+			delete(v, pair[0])
+			if limit, ok := v[pair[1]]; ok && exclusive {
+				v[pair[0]] = limit
+				delete(v, pair[1])
+			}
+		}
+
+		for _, item := range v {
+			convertSchemas(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			convertSchemas(item)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const spec = `
+openapi: 3.0.3
+servers:
+  - url: https://example-api.com/api
+paths:
+  /users/{id}:
+    get:
+      responses:
+        200:
+          description: Пользователь
+          headers:
+            X-Rate-Limit:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+        4XX:
+          description: Ошибка
+  /users/me:
+    get:
+      responses:
+        200:
+          description: Текущий пользователь
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        email:
+          type: string
+          nullable: true
+`
+
+func TestCheck(t *testing.T) {
+	s, err := Parse([]byte(spec))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json; charset=utf-8")
+	headers.Set("X-Rate-Limit", "100")
+
+	operation, violations := s.Check(Exchange{
+		Method:          "GET",
+		Path:            "/api/users/5",
+		Status:          200,
+		ResponseHeaders: headers,
+		ResponseBody:    []byte(`{"id":5,"name":"Ivan","email":null}`),
+	})
+	assert.Equal(t, "GET /users/{id}", operation)
+	assert.Empty(t, violations)
+
+	operation, violations = s.Check(Exchange{
+		Method:          "GET",
+		Path:            "/api/users/me",
+		Status:          200,
+		ResponseHeaders: http.Header{},
+	})
+	assert.Equal(t, "GET /users/me", operation)
+	assert.Empty(t, violations)
+
+	_, violations = s.Check(Exchange{
+		Method:          "GET",
+		Path:            "/api/users/5",
+		Status:          200,
+		ResponseHeaders: http.Header{"Content-Type": []string{"application/json"}},
+		ResponseBody:    []byte(`{"id":"5","phone":"+7"}`),
+	})
+	assert.Equal(t, []string{
+		"header X-Rate-Limit: required header 'X-Rate-Limit' is missing",
+		"body /: body /: required property 'name' is missing",
+		"body /id: body /id: expected type integer, got string",
+		"body /phone: body field '/phone' is not documented (warning)",
+	}, format(violations))
+
+	_, violations = s.Check(Exchange{Method: "GET", Path: "/api/users/5", Status: 500, ResponseHeaders: http.Header{}})
+	assert.Equal(t, []string{"status: status 500 is not documented for GET /users/{id}"}, format(violations))
+
+	_, violations = s.Check(Exchange{Method: "GET", Path: "/api/users/5", Status: 404, ResponseHeaders: http.Header{}})
+	assert.Empty(t, violations)
+
+	_, violations = s.Check(Exchange{Method: "DELETE", Path: "/api/users/5"})
+	assert.Equal(t, []string{"operation: operation DELETE /api/users/5 is not documented (warning)"}, format(violations))
+}
+
+func format(violations []Violation) []string {
+	var list []string
+	for _, v := range violations {
+		s := v.Key + ": " + v.Err.Error()
+		if v.Warning {
+			s += " (warning)"
+		}
+		list = append(list, s)
+	}
+
+	return list
+}
+
+func TestPathItemRef(t *testing.T) {
+	s, err := Parse([]byte(`
+openapi: 3.1.0
+paths:
+  /orders:
+    $ref: '#/components/pathItems/Orders'
+  /orders/latest:
+    $ref: '#/paths/~1orders'
+components:
+  pathItems:
+    Orders:
+      get:
+        responses:
+          200:
+            description: Заказы
+`))
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var list []string
+	for _, op := range s.Operations() {
+		list = append(list, op.Method+" "+op.Path)
+	}
+	assert.Equal(t, []string{"GET /orders", "GET /orders/latest"}, list)
+
+	operation, violations := s.Check(Exchange{Method: "GET", Path: "/orders", Status: 200, ResponseHeaders: http.Header{}})
+	assert.Equal(t, "GET /orders", operation)
+	assert.Empty(t, violations)
+
+	_, err = Parse([]byte(`
+openapi: 3.0.3
+paths:
+  /orders:
+    $ref: '#/components/pathItems/Missing'
+`))
+	assert.EqualError(t, err, "path '/orders': $ref '#/components/pathItems/Missing' not found")
+
+	_, err = Parse([]byte(`
+openapi: 3.0.3
+paths:
+  /orders:
+    $ref: 'orders.yaml'
+`))
+	assert.EqualError(t, err, "path '/orders': unsupported $ref 'orders.yaml': only local references are supported")
+}
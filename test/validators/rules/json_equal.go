@@ -0,0 +1,61 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/MashinaMashina/api-tests/test/validators/jsondiff"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonpath"
+	"github.com/MashinaMashina/api-tests/test/validators/jsonschema"
+)
+
+// validJSONEqual сравнивает значение с JSON из json-equal по смыслу, а не побайтово.
+// Значение может быть строкой с JSON, сырым JSON объекта ([]byte) или элементами массива ([][]byte).
+func (r Rule) validJSONEqual(value interface{}) error {
+	expected, err := jsonschema.Decode([]byte(*r.JSONEqual))
+	if err != nil {
+		return fmt.Errorf("parsing json-equal: %w", err)
+	}
+
+	actual, err := jsonschema.Decode(rawJSON(value))
+	if err != nil {
+		return fmt.Errorf("is not a json: %w", err)
+	}
+
+	for _, p := range r.IgnorePaths {
+		path, err := jsonpath.Parse(p)
+		if err != nil {
+			return fmt.Errorf("ignore-paths: %w", err)
+		}
+
+		path.Replace(expected, jsondiff.Ignored)
+		path.Replace(actual, jsondiff.Ignored)
+	}
+
+	changes := jsondiff.Compare(expected, actual, jsondiff.Options{
+		IgnoreOrder: r.IgnoreOrder != nil && *r.IgnoreOrder,
+		IgnoreExtra: r.IgnoreExtra != nil && *r.IgnoreExtra,
+	})
+	if len(changes) > 0 {
+		return fmt.Errorf("json differs: %s", jsondiff.Format(changes))
+	}
+
+	return nil
+}
+
+// rawJSON возвращает JSON текст значения, полученного валидатором
+func rawJSON(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	case [][]byte:
+		return append(append([]byte("["), bytes.Join(v, []byte(","))...), ']')
+	}
+
+	b, _ := json.Marshal(value)
+
+	return b
+}
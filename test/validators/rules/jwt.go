@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWTVerify - способ проверки подписи JWT. Указывается один из параметров.
+type JWTVerify struct {
+	// Secret - секрет для алгоритмов HMAC (HS256 и т.д.)
+	Secret string `yaml:"secret,omitempty"`
+	// KeyFile - PEM файл с публичным ключом RSA или ECDSA
+	KeyFile string `yaml:"key-file,omitempty"`
+	// JWKSFile - файл JWKS, ключ выбирается по kid из заголовка токена
+	JWKSFile string `yaml:"jwks-file,omitempty"`
+}
+
+// validJWT разбирает токен, проверяет подпись, если задан verify, и срок действия exp.
+// Остальные проверки claims описываются вложенными правилами fields.
+func (r Rule) validJWT(token string) error {
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	claims := jwt.MapClaims{}
+
+	if r.Verify == nil {
+		if _, _, err := parser.ParseUnverified(token, claims); err != nil {
+			return fmt.Errorf("jwt token is invalid")
+		}
+	} else if _, err := parser.ParseWithClaims(token, claims, r.Verify.key); err != nil {
+		return fmt.Errorf("jwt signature is invalid: %w", err)
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), false) {
+		return fmt.Errorf("jwt token is expired")
+	}
+
+	return nil
+}
+
+// JWTClaims возвращает claims токена в виде JSON без проверки подписи
+func JWTClaims(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("jwt token is invalid")
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding jwt claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// key возвращает ключ для проверки подписи токена.
+// Алгоритм токена должен подходить к виду ключа, иначе подпись можно подделать.
+func (v JWTVerify) key(token *jwt.Token) (interface{}, error) {
+	switch {
+	case v.Secret != "":
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method '%s' for secret", token.Method.Alg())
+		}
+
+		return []byte(v.Secret), nil
+	case v.KeyFile != "":
+		pem, err := ioutil.ReadFile(v.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return jwt.ParseRSAPublicKeyFromPEM(pem)
+		case *jwt.SigningMethodECDSA:
+			return jwt.ParseECPublicKeyFromPEM(pem)
+		}
+
+		return nil, fmt.Errorf("unexpected signing method '%s' for public key", token.Method.Alg())
+	case v.JWKSFile != "":
+		return v.jwksKey(token)
+	}
+
+	return nil, fmt.Errorf("verify must have secret, key-file or jwks-file")
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// jwksKey ищет ключ в JWKS файле по kid из заголовка токена.
+// Без kid подходит единственный ключ файла.
+func (v JWTVerify) jwksKey(token *jwt.Token) (interface{}, error) {
+	b, err := ioutil.ReadFile(v.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading jwks file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("decoding jwks file: %w", err)
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	for _, key := range set.Keys {
+		if key.Kid == kid || (kid == "" && len(set.Keys) == 1) {
+			return key.public(token.Method)
+		}
+	}
+
+	return nil, fmt.Errorf("key '%s' not found in jwks", kid)
+}
+
+func (k jwk) public(method jwt.SigningMethod) (interface{}, error) {
+	if k.Alg != "" && k.Alg != method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method '%s' for key with alg '%s'", method.Alg(), k.Alg)
+	}
+
+	switch k.Kty {
+	case "RSA":
+		if _, ok := method.(*jwt.SigningMethodRSA); !ok {
+			if _, ok = method.(*jwt.SigningMethodRSAPSS); !ok {
+				return nil, fmt.Errorf("unexpected signing method '%s' for RSA key", method.Alg())
+			}
+		}
+
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decoding jwk n: %w", err)
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decoding jwk e: %w", err)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if _, ok := method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method '%s' for EC key", method.Alg())
+		}
+
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported jwk curve '%s'", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decoding jwk x: %w", err)
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decoding jwk y: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		if _, ok := method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method '%s' for oct key", method.Alg())
+		}
+
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(k.K, "="))
+	}
+
+	return nil, fmt.Errorf("unsupported jwk key type '%s'", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
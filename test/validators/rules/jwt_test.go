@@ -0,0 +1,88 @@
+package rules
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims, kid string) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	s, err := token.SignedString(key)
+	assert.Nil(t, err)
+
+	return s
+}
+
+func TestJWTVerify(t *testing.T) {
+	dir := t.TempDir()
+	claims := jwt.MapClaims{"sub": "42", "exp": time.Now().Add(time.Hour).Unix()}
+
+	// HMAC
+	hmacToken := sign(t, jwt.SigningMethodHS256, []byte("secret"), claims, "")
+	assert.Nil(t, Rule{Type: "jwt", Verify: &JWTVerify{Secret: "secret"}}.Valid(hmacToken))
+	assert.EqualError(t, Rule{Type: "jwt", Verify: &JWTVerify{Secret: "other"}}.Valid(hmacToken),
+		"jwt signature is invalid: signature is invalid")
+
+	// RSA из PEM файла
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.Nil(t, err)
+	keyFile := filepath.Join(dir, "public.pem")
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+
+	rsaToken := sign(t, jwt.SigningMethodRS256, rsaKey, claims, "rsa-1")
+	assert.Nil(t, Rule{Type: "jwt", Verify: &JWTVerify{KeyFile: keyFile}}.Valid(rsaToken))
+
+	// Токен HS256, подписанный публичным ключом как секретом, не должен пройти
+	forged := sign(t, jwt.SigningMethodHS256, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), claims, "")
+	assert.EqualError(t, Rule{Type: "jwt", Verify: &JWTVerify{KeyFile: keyFile}}.Valid(forged),
+		"jwt signature is invalid: unexpected signing method 'HS256' for public key")
+
+	// JWKS с RSA и EC ключами
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	b64 := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	jwks := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"rsa-1","n":"%s","e":"AQAB"},{"kty":"EC","kid":"ec-1","crv":"P-256","x":"%s","y":"%s"}]}`,
+		b64(rsaKey.N), b64(ecKey.X), b64(ecKey.Y))
+	jwksFile := filepath.Join(dir, "jwks.json")
+	assert.Nil(t, os.WriteFile(jwksFile, []byte(jwks), 0o644))
+
+	rule := Rule{Type: "jwt", Verify: &JWTVerify{JWKSFile: jwksFile}}
+	assert.Nil(t, rule.Valid(rsaToken))
+	assert.Nil(t, rule.Valid(sign(t, jwt.SigningMethodES256, ecKey, claims, "ec-1")))
+	assert.EqualError(t, rule.Valid(sign(t, jwt.SigningMethodES256, ecKey, claims, "unknown")),
+		"jwt signature is invalid: key 'unknown' not found in jwks")
+}
+
+func TestJWTClaims(t *testing.T) {
+	expired := sign(t, jwt.SigningMethodHS256, []byte("secret"), jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}, "")
+	assert.EqualError(t, Rule{Type: "jwt"}.Valid(expired), "jwt token is expired")
+
+	token := sign(t, jwt.SigningMethodHS256, []byte("secret"), jwt.MapClaims{"sub": "42", "roles": []string{"admin"}}, "")
+	claims, err := JWTClaims(token)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"sub":"42","roles":["admin"]}`, string(claims))
+
+	_, err = JWTClaims("abc")
+	assert.EqualError(t, err, "jwt token is invalid")
+}
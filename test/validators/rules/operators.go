@@ -0,0 +1,255 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validOperators проверяет операторы, которые работают одинаково для всех валидаторов:
+// regex, contains, not-contains, one-of, min-length, max-length и between.
+// Числа сравниваются как текст в regex, contains и one-of, поэтому работают и с HTTP кодом.
+func (r Rule) validOperators(value interface{}) error {
+	text := toText(value)
+
+	if r.Regex != nil {
+		re, err := regexp.Compile(*r.Regex)
+		if err != nil {
+			return fmt.Errorf("parsing Regex '%s': %w", *r.Regex, err)
+		}
+
+		if !re.MatchString(text) {
+			return fmt.Errorf("must match regex '%s'", *r.Regex)
+		}
+	}
+
+	if r.Contains != nil && !contains(value, *r.Contains) {
+		return fmt.Errorf("must contain '%s'", *r.Contains)
+	}
+
+	if r.NotContains != nil && contains(value, *r.NotContains) {
+		return fmt.Errorf("must not contain '%s'", *r.NotContains)
+	}
+
+	if len(r.OneOf) > 0 && !r.oneOf(value, text) {
+		return fmt.Errorf("must be one of '%s'", strings.Join(r.OneOf, "', '"))
+	}
+
+	if r.MinLength != nil || r.MaxLength != nil {
+		if err := r.validLength(value); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Between) > 0 {
+		if err := r.validBetween(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Captures возвращает именованные группы regex, найденные в значении.
+// Если regex не задан или не совпал, возвращает nil.
+func (r Rule) Captures(value interface{}) map[string]string {
+	if r.Regex == nil {
+		return nil
+	}
+
+	re, err := regexp.Compile(*r.Regex)
+	if err != nil {
+		return nil
+	}
+
+	match := re.FindStringSubmatch(toText(value))
+	if match == nil {
+		return nil
+	}
+
+	captures := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			captures[name] = match[i]
+		}
+	}
+
+	return captures
+}
+
+func (r Rule) oneOf(value interface{}, text string) bool {
+	num, isNum := toNumber(value)
+
+	for _, option := range r.OneOf {
+		if option == text {
+			return true
+		}
+
+		if isNum {
+			if f, err := strconv.ParseFloat(option, 64); err == nil && f == num {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r Rule) validLength(value interface{}) error {
+	var length int
+
+	switch v := value.(type) {
+	case string:
+		length = utf8.RuneCountInString(v)
+	case [][]byte:
+		length = len(v)
+	default:
+		return fmt.Errorf("length check is not supported for %s", r.Type)
+	}
+
+	if r.MinLength != nil {
+		minLength, err := strconv.Atoi(*r.MinLength)
+		if err != nil {
+			return fmt.Errorf("parsing MinLength '%s' as int: %w", *r.MinLength, err)
+		}
+
+		if length < minLength {
+			return fmt.Errorf("length must be at least %d, got %d", minLength, length)
+		}
+	}
+
+	if r.MaxLength != nil {
+		maxLength, err := strconv.Atoi(*r.MaxLength)
+		if err != nil {
+			return fmt.Errorf("parsing MaxLength '%s' as int: %w", *r.MaxLength, err)
+		}
+
+		if length > maxLength {
+			return fmt.Errorf("length must be at most %d, got %d", maxLength, length)
+		}
+	}
+
+	return nil
+}
+
+func (r Rule) validBetween(value interface{}) error {
+	if len(r.Between) != 2 {
+		return fmt.Errorf("between must have two values, got %d", len(r.Between))
+	}
+
+	num, ok := toNumber(value)
+	if !ok {
+		return fmt.Errorf("between check is not supported for %s", r.Type)
+	}
+
+	from, err := strconv.ParseFloat(r.Between[0], 64)
+	if err != nil {
+		return fmt.Errorf("parsing Between '%s' as float: %w", r.Between[0], err)
+	}
+
+	to, err := strconv.ParseFloat(r.Between[1], 64)
+	if err != nil {
+		return fmt.Errorf("parsing Between '%s' as float: %w", r.Between[1], err)
+	}
+
+	if num < from || num > to {
+		return fmt.Errorf("must be between '%v' and '%v'", from, to)
+	}
+
+	return nil
+}
+
+// contains проверяет вхождение подстроки, а для массива - наличие элемента
+func contains(value interface{}, sub string) bool {
+	if items, ok := value.([][]byte); ok {
+		for _, item := range items {
+			if toText(item) == sub {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return strings.Contains(toText(value), sub)
+}
+
+// toText приводит значение к тексту. Строки в элементах массива возвращаются без кавычек.
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case NullValue:
+		return "null"
+	case []byte:
+		var s string
+		if bytes.HasPrefix(v, []byte(`"`)) && json.Unmarshal(v, &s) == nil {
+			return s
+		}
+
+		return string(v)
+	case [][]byte:
+		return string(rawJSON(v))
+	}
+
+	return fmt.Sprint(value)
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// validItems проверяет количество элементов массива: count, min-items и max-items
+func (r Rule) validItems(count int) error {
+	if r.Count != nil {
+		expected, err := strconv.Atoi(*r.Count)
+		if err != nil {
+			return fmt.Errorf("parsing Count '%s' as int: %w", *r.Count, err)
+		}
+
+		if count != expected {
+			return fmt.Errorf("must have %d items, got %d", expected, count)
+		}
+	}
+
+	if r.MinItems != nil {
+		minItems, err := strconv.Atoi(*r.MinItems)
+		if err != nil {
+			return fmt.Errorf("parsing MinItems '%s' as int: %w", *r.MinItems, err)
+		}
+
+		if count < minItems {
+			return fmt.Errorf("must have at least %d items, got %d", minItems, count)
+		}
+	}
+
+	if r.MaxItems != nil {
+		maxItems, err := strconv.Atoi(*r.MaxItems)
+		if err != nil {
+			return fmt.Errorf("parsing MaxItems '%s' as int: %w", *r.MaxItems, err)
+		}
+
+		if count > maxItems {
+			return fmt.Errorf("must have at most %d items, got %d", maxItems, count)
+		}
+	}
+
+	return nil
+}
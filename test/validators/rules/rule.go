@@ -3,10 +3,9 @@ package rules
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
-
-	"github.com/golang-jwt/jwt/v4"
 )
 
 type RuleType string
@@ -21,27 +20,169 @@ const (
 	TypeHEX     RuleType = "hex"
 	TypeObject  RuleType = "object"
 	TypeArray   RuleType = "array"
+
+	TypeUUID     RuleType = "uuid"
+	TypeEmail    RuleType = "email"
+	TypeURL      RuleType = "url"
+	TypeDatetime RuleType = "datetime"
+	TypeIP       RuleType = "ip"
+	TypeBase64   RuleType = "base64"
+	TypeNull     RuleType = "null"
+)
+
+// Уровни важности правила (поле severity).
+// Не прошедшее правило с уровнем error роняет тест, остальные только пишутся в лог и отчет.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
 )
 
+// ParseSeverity приводит уровень важности из описания правила к одной из констант.
+// Пустое или неизвестное значение считается уровнем error.
+func ParseSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case SeverityWarning, "warn":
+		return SeverityWarning
+	case SeverityInfo:
+		return SeverityInfo
+	default:
+		return SeverityError
+	}
+}
+
 // Rule - правило валидации какого-либо значения
 type Rule struct {
-	Type     RuleType `yaml:"type"`
-	Key      string   `yaml:"key"`
-	Equal    *string  `yaml:"equal"`
-	NotEqual *string  `yaml:"not-equal"`
-	Less     *string  `yaml:"less"`
-	Greater  *string  `yaml:"greater"`
-	Prefix   *string  `yaml:"prefix"`
-	Suffix   *string  `yaml:"suffix"`
-	Store    *string  `yaml:"store"`
-	Severity *string  `yaml:"severity"`
-	Required *bool    `yaml:"required"`
-	Fields   []Rule   `yaml:"fields"`
+	Type     RuleType `yaml:"type,omitempty"`
+	Key      string   `yaml:"key,omitempty"`
+	Equal    *string  `yaml:"equal,omitempty"`
+	NotEqual *string  `yaml:"not-equal,omitempty"`
+	Less     *string  `yaml:"less,omitempty"`
+	Greater  *string  `yaml:"greater,omitempty"`
+	Prefix   *string  `yaml:"prefix,omitempty"`
+	Suffix   *string  `yaml:"suffix,omitempty"`
+	// Regex - регулярное выражение, именованные группы сохраняются в хранилище
+	Regex       *string  `yaml:"regex,omitempty"`
+	Contains    *string  `yaml:"contains,omitempty"`
+	NotContains *string  `yaml:"not-contains,omitempty"`
+	OneOf       []string `yaml:"one-of,omitempty"`
+	MinLength   *string  `yaml:"min-length,omitempty"`
+	MaxLength   *string  `yaml:"max-length,omitempty"`
+	// Between - диапазон чисел из двух значений, границы включаются
+	Between []string `yaml:"between,omitempty"`
+	// Before, After - границы для datetime: время в RFC3339 или относительно текущего, например now-5m
+	Before *string `yaml:"before,omitempty"`
+	After  *string `yaml:"after,omitempty"`
+	// Verify - проверка подписи для типа jwt
+	Verify *JWTVerify `yaml:"verify,omitempty"`
+	// Absent - значения не должно быть
+	Absent *bool `yaml:"absent,omitempty"`
+	// NoAdditional - для object: поля, которых нет в fields, считаются ошибкой
+	NoAdditional *bool `yaml:"no-additional,omitempty"`
+	// Count, MinItems, MaxItems - количество элементов массива
+	Count    *string `yaml:"count,omitempty"`
+	MinItems *string `yaml:"min-items,omitempty"`
+	MaxItems *string `yaml:"max-items,omitempty"`
+	Store    *string `yaml:"store,omitempty"`
+	// StorePath - путь внутри значения, который нужно сохранить вместо всего значения
+	StorePath *string `yaml:"store-path,omitempty"`
+	Severity  *string `yaml:"severity,omitempty"`
+	Required  *bool   `yaml:"required,omitempty"`
+	Fields    []Rule  `yaml:"fields,omitempty"`
+	// JSONEqual - сравнение с JSON по смыслу: порядок ключей и форматирование не важны
+	JSONEqual *string `yaml:"json-equal,omitempty"`
+	// IgnoreOrder - для json-equal: массивы сравниваются без учета порядка
+	IgnoreOrder *bool `yaml:"ignore-order,omitempty"`
+	// IgnoreExtra - для json-equal: лишние поля объектов не считаются ошибкой
+	IgnoreExtra *bool `yaml:"ignore-extra,omitempty"`
+	// IgnorePaths - для json-equal: JSONPath пути, которые не сравниваются
+	IgnorePaths []string `yaml:"ignore-paths,omitempty"`
+}
+
+// Error - ошибка проверки значения правилом.
+// Кроме текста ошибки хранит фактическое значение, чтобы его можно было вывести в отчете.
+type Error struct {
+	Expected string
+	Actual   interface{}
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
 }
 
 // Valid функция проверяет валидность значения согласно описанным правилам.
-// Если всё верно - error равно nil
+// Если всё верно - error равно nil, иначе возвращается *Error
 func (r Rule) Valid(value interface{}) error {
+	if err := r.valid(value); err != nil {
+		return &Error{Expected: r.Expected(), Actual: value, Err: err}
+	}
+
+	return nil
+}
+
+// Expected описывает ожидания правила в виде текста, например: equal '200', less '5'
+func (r Rule) Expected() string {
+	var parts []string
+
+	add := func(name string, value *string) {
+		if value != nil {
+			parts = append(parts, fmt.Sprintf("%s '%s'", name, *value))
+		}
+	}
+
+	add("equal", r.Equal)
+	add("not-equal", r.NotEqual)
+	add("less", r.Less)
+	add("greater", r.Greater)
+	add("prefix", r.Prefix)
+	add("suffix", r.Suffix)
+	add("json-equal", r.JSONEqual)
+	add("regex", r.Regex)
+	add("contains", r.Contains)
+	add("not-contains", r.NotContains)
+	add("min-length", r.MinLength)
+	add("max-length", r.MaxLength)
+	add("before", r.Before)
+	add("after", r.After)
+	add("count", r.Count)
+	add("min-items", r.MinItems)
+	add("max-items", r.MaxItems)
+
+	if r.Absent != nil && *r.Absent {
+		parts = append(parts, "absent")
+	}
+	if r.NoAdditional != nil && *r.NoAdditional {
+		parts = append(parts, "no-additional")
+	}
+
+	if len(r.OneOf) > 0 {
+		parts = append(parts, fmt.Sprintf("one-of '%s'", strings.Join(r.OneOf, "', '")))
+	}
+	if len(r.Between) > 0 {
+		parts = append(parts, fmt.Sprintf("between '%s'", strings.Join(r.Between, "' and '")))
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, string(r.Type))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (r Rule) valid(value interface{}) error {
+	if r.Absent != nil && *r.Absent {
+		if value != nil {
+			return fmt.Errorf("must be absent")
+		}
+
+		return nil
+	}
+
 	if value == nil {
 		// Если указано, что поле не обязательное - нет ошибки
 		if r.Required != nil && !*r.Required {
@@ -51,6 +192,21 @@ func (r Rule) Valid(value interface{}) error {
 		}
 	}
 
+	if r.JSONEqual != nil {
+		if err := r.validJSONEqual(value); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validType(value); err != nil {
+		return err
+	}
+
+	return r.validOperators(value)
+}
+
+// validType проверяет тип значения и операторы, которые зависят от типа
+func (r Rule) validType(value interface{}) error {
 	switch r.Type {
 	case TypeBoolean:
 		if v, ok := value.(bool); ok {
@@ -58,7 +214,7 @@ func (r Rule) Valid(value interface{}) error {
 		} else {
 			return fmt.Errorf("is not a boolean")
 		}
-	case TypeFloat, TypeInteger:
+	case TypeFloat:
 		if i, isInt := value.(int); isInt {
 			return r.validFloat(float64(i))
 		} else if v, isFloat := value.(float64); isFloat {
@@ -66,6 +222,14 @@ func (r Rule) Valid(value interface{}) error {
 		} else {
 			return fmt.Errorf("is not a int or float")
 		}
+	case TypeInteger:
+		if i, isInt := value.(int); isInt {
+			return r.validFloat(float64(i))
+		} else if v, isFloat := value.(float64); isFloat && v == math.Trunc(v) {
+			return r.validFloat(v)
+		} else {
+			return fmt.Errorf("is not an integer")
+		}
 	case TypeString:
 		if v, ok := value.(string); ok {
 			return r.validString(v)
@@ -92,7 +256,29 @@ func (r Rule) Valid(value interface{}) error {
 		} else {
 			return fmt.Errorf("is not a string")
 		}
-	case TypeObject, TypeArray:
+	case TypeUUID, TypeEmail, TypeURL, TypeDatetime, TypeIP, TypeBase64:
+		if v, ok := value.(string); ok {
+			if err := r.validString(v); err != nil {
+				return err
+			}
+
+			return r.validFormat(v)
+		} else {
+			return fmt.Errorf("is not a string")
+		}
+	case TypeNull:
+		if _, ok := value.(NullValue); !ok {
+			return fmt.Errorf("is not a null")
+		}
+
+		return nil
+	case TypeArray:
+		if items, ok := value.([][]byte); ok {
+			return r.validItems(len(items))
+		}
+
+		return nil
+	case TypeObject:
 		return nil
 	default:
 		return fmt.Errorf("invalid rule type '%s'", r.Type)
@@ -194,7 +380,7 @@ func (r Rule) validString(val string) error {
 
 	if r.NotEqual != nil {
 		if *r.NotEqual == val {
-			return fmt.Errorf("must not be '%s'", *r.Equal)
+			return fmt.Errorf("must not be '%s'", *r.NotEqual)
 		}
 	}
 
@@ -213,18 +399,6 @@ func (r Rule) validString(val string) error {
 	return nil
 }
 
-func (r Rule) validJWT(token string) error {
-	res, _ := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte{}, nil
-	})
-
-	if res == nil {
-		return fmt.Errorf("jwt token is invalid")
-	}
-
-	return nil
-}
-
 func (_ Rule) validHex(id string) error {
 	_, err := hex.DecodeString(id)
 
@@ -2,6 +2,7 @@ package rules
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -58,6 +59,18 @@ func TestValid(t *testing.T) {
 			rule:      Rule{Type: "string", Equal: &varStr},
 			expectErr: "must be 'any-string'",
 		},
+		{
+			Name:      "Проверка строки на несоответствие. Положительный результат",
+			input:     "invalid-value",
+			rule:      Rule{Type: "string", NotEqual: &varStr},
+			expectErr: "",
+		},
+		{
+			Name:      "Проверка строки на несоответствие. Ждем ошибку",
+			input:     varStr,
+			rule:      Rule{Type: "string", NotEqual: &varStr},
+			expectErr: "must not be 'any-string'",
+		},
 		{
 			Name:      "Проверка префикса строки.",
 			input:     "any-string-value",
@@ -161,3 +174,139 @@ func TestValid(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorActual(t *testing.T) {
+	var5 := "5"
+	rule := Rule{Type: "float", Less: &var5, Greater: &var5}
+
+	err := rule.Valid(7.0)
+
+	var ruleErr *Error
+	if assert.ErrorAs(t, err, &ruleErr) {
+		assert.Equal(t, 7.0, ruleErr.Actual)
+		assert.Equal(t, "less '5', greater '5'", ruleErr.Expected)
+		assert.EqualError(t, ruleErr, "must less then '5'")
+	}
+
+	assert.Equal(t, "hex", Rule{Type: "hex"}.Expected())
+}
+
+func TestParseSeverity(t *testing.T) {
+	assert.Equal(t, SeverityError, ParseSeverity(""))
+	assert.Equal(t, SeverityError, ParseSeverity("Error"))
+	assert.Equal(t, SeverityWarning, ParseSeverity("warning"))
+	assert.Equal(t, SeverityWarning, ParseSeverity("WARN"))
+	assert.Equal(t, SeverityInfo, ParseSeverity(" info "))
+	assert.Equal(t, SeverityError, ParseSeverity("unknown"))
+}
+
+func TestJSONEqual(t *testing.T) {
+	expected := `{"id": 1, "tags": ["a", "b"], "profile": {"name": "Ivan"}}`
+	yes := true
+
+	rule := Rule{Type: "string", JSONEqual: &expected}
+	assert.Nil(t, rule.Valid(`{"profile":{"name":"Ivan"},"tags":["a","b"],"id":1.0}`))
+	assert.EqualError(t, rule.Valid(`{"id":2,"tags":["b","a"],"profile":{"name":"Ivan"},"extra":true}`),
+		`json differs: /extra: unexpected true; /id: expected 1, actual 2; /tags/0: expected "a", actual "b"; /tags/1: expected "b", actual "a"`)
+	assert.EqualError(t, rule.Valid(`{"id":`), "is not a json: unexpected EOF")
+
+	rule = Rule{Type: "object", JSONEqual: &expected, IgnoreOrder: &yes, IgnoreExtra: &yes, IgnorePaths: []string{"id"}}
+	assert.Nil(t, rule.Valid([]byte(`{"id":2,"tags":["b","a"],"profile":{"name":"Ivan","age":30},"extra":true}`)))
+
+	array := `[1, 2]`
+	rule = Rule{Type: "array", JSONEqual: &array}
+	assert.Nil(t, rule.Valid([][]byte{[]byte("1"), []byte("2")}))
+}
+
+func TestOperators(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	cases := []ValidatingCase{
+		{Name: "regex", input: "order-123", rule: Rule{Type: "string", Regex: str(`^order-\d+$`)}},
+		{Name: "regex ошибка", input: "order-x", rule: Rule{Type: "string", Regex: str(`^order-\d+$`)}, expectErr: `must match regex '^order-\d+$'`},
+		{Name: "regex по коду", input: 201, rule: Rule{Type: "integer", Regex: str(`^2\d\d$`)}},
+		{Name: "contains", input: "Bearer abc", rule: Rule{Type: "string", Contains: str("abc")}},
+		{Name: "contains ошибка", input: "Bearer abc", rule: Rule{Type: "string", Contains: str("xyz")}, expectErr: "must contain 'xyz'"},
+		{Name: "contains в массиве", input: [][]byte{[]byte(`"admin"`), []byte(`"user"`)}, rule: Rule{Type: "array", Contains: str("admin")}},
+		{Name: "contains в массиве не подстрока", input: [][]byte{[]byte(`"administrator"`)}, rule: Rule{Type: "array", Contains: str("admin")}, expectErr: "must contain 'admin'"},
+		{Name: "not-contains", input: "ok", rule: Rule{Type: "string", NotContains: str("error")}},
+		{Name: "not-contains ошибка", input: "fatal error", rule: Rule{Type: "string", NotContains: str("error")}, expectErr: "must not contain 'error'"},
+		{Name: "one-of", input: "paid", rule: Rule{Type: "string", OneOf: []string{"new", "paid"}}},
+		{Name: "one-of число", input: 2.0, rule: Rule{Type: "float", OneOf: []string{"1", "2"}}},
+		{Name: "one-of ошибка", input: 204, rule: Rule{Type: "integer", OneOf: []string{"200", "201"}}, expectErr: "must be one of '200', '201'"},
+		{Name: "min-length", input: "Иван", rule: Rule{Type: "string", MinLength: str("4"), MaxLength: str("4")}},
+		{Name: "min-length ошибка", input: "Ив", rule: Rule{Type: "string", MinLength: str("3")}, expectErr: "length must be at least 3, got 2"},
+		{Name: "max-length массива", input: [][]byte{[]byte("1"), []byte("2")}, rule: Rule{Type: "array", MaxLength: str("1")}, expectErr: "length must be at most 1, got 2"},
+		{Name: "length числа", input: 5.0, rule: Rule{Type: "float", MinLength: str("1")}, expectErr: "length check is not supported for float"},
+		{Name: "between", input: 200, rule: Rule{Type: "integer", Between: []string{"200", "299"}}},
+		{Name: "between ошибка", input: 10.5, rule: Rule{Type: "float", Between: []string{"1", "10"}}, expectErr: "must be between '1' and '10'"},
+		{Name: "between без границы", input: 1.0, rule: Rule{Type: "float", Between: []string{"1"}}, expectErr: "between must have two values, got 1"},
+	}
+
+	for _, curCase := range cases {
+		err := curCase.rule.Valid(curCase.input)
+
+		if curCase.expectErr == "" {
+			assert.Nil(t, err, curCase.Name)
+		} else {
+			assert.EqualError(t, err, curCase.expectErr, curCase.Name)
+		}
+	}
+
+	rule := Rule{Regex: str(`^/api/orders/(?P<orderId>\d+)/(\w+)$`)}
+	assert.Equal(t, map[string]string{"orderId": "42"}, rule.Captures("/api/orders/42/items"))
+	assert.Nil(t, rule.Captures("/api/users"))
+	assert.Equal(t, "regex '^/api/orders/(?P<orderId>\\d+)/(\\w+)$'", rule.Expected())
+}
+
+func TestTypes(t *testing.T) {
+	str := func(s string) *string { return &s }
+	now := time.Now().UTC()
+
+	cases := []ValidatingCase{
+		{Name: "uuid", input: "3f2504e0-4f89-11d3-9a0c-0305e82c3301", rule: Rule{Type: "uuid"}},
+		{Name: "uuid ошибка", input: "3f2504e0-4f89-11d3", rule: Rule{Type: "uuid"}, expectErr: "is not a uuid"},
+		{Name: "email", input: "ivan@mail.com", rule: Rule{Type: "email", Suffix: str("@mail.com")}},
+		{Name: "email ошибка", input: "Ivan <ivan@mail.com>", rule: Rule{Type: "email"}, expectErr: "is not an email"},
+		{Name: "url", input: "https://example.com/api?x=1", rule: Rule{Type: "url"}},
+		{Name: "url ошибка", input: "/api/users", rule: Rule{Type: "url"}, expectErr: "is not an absolute url"},
+		{Name: "ip", input: "::1", rule: Rule{Type: "ip"}},
+		{Name: "ip ошибка", input: "300.1.1.1", rule: Rule{Type: "ip"}, expectErr: "is not an ip address"},
+		{Name: "base64", input: "aXZhbg==", rule: Rule{Type: "base64"}},
+		{Name: "base64 ошибка", input: "не base64", rule: Rule{Type: "base64"}, expectErr: "is not a base64"},
+		{Name: "null", input: NullValue{}, rule: Rule{Type: "null"}},
+		{Name: "null ошибка", input: "0", rule: Rule{Type: "null"}, expectErr: "is not a null"},
+		{Name: "integer", input: 3.0, rule: Rule{Type: "integer"}},
+		{Name: "integer дробное", input: 3.5, rule: Rule{Type: "integer"}, expectErr: "is not an integer"},
+		{Name: "datetime", input: now.Add(-time.Minute).Format(time.RFC3339), rule: Rule{Type: "datetime", After: str("now-5m"), Before: str("now")}},
+		{Name: "datetime ошибка формата", input: "2022-09-01 10:00", rule: Rule{Type: "datetime"}, expectErr: "is not a datetime in RFC3339"},
+		{Name: "datetime старое", input: "2022-09-01T10:00:00Z", rule: Rule{Type: "datetime", After: str("2022-09-01T10:00:00Z")}, expectErr: "must be after '2022-09-01T10:00:00Z'"},
+		{Name: "datetime ошибка границы", input: "2022-09-01T10:00:00Z", rule: Rule{Type: "datetime", Before: str("now-x")}, expectErr: `parsing Before 'now-x': time: invalid duration "-x"`},
+	}
+
+	for _, curCase := range cases {
+		err := curCase.rule.Valid(curCase.input)
+
+		if curCase.expectErr == "" {
+			assert.Nil(t, err, curCase.Name)
+		} else {
+			assert.EqualError(t, err, curCase.expectErr, curCase.Name)
+		}
+	}
+}
+
+func TestNegative(t *testing.T) {
+	yes := true
+	str := func(s string) *string { return &s }
+	items := [][]byte{[]byte("1"), []byte("2")}
+
+	assert.Nil(t, Rule{Absent: &yes}.Valid(nil))
+	assert.EqualError(t, Rule{Type: "string", Absent: &yes}.Valid("hash"), "must be absent")
+	assert.Equal(t, "absent", Rule{Absent: &yes}.Expected())
+
+	assert.Nil(t, Rule{Type: "array", Count: str("0")}.Valid([][]byte{}))
+	assert.EqualError(t, Rule{Type: "array", Count: str("0")}.Valid(items), "must have 0 items, got 2")
+	assert.Nil(t, Rule{Type: "array", MinItems: str("1"), MaxItems: str("2")}.Valid(items))
+	assert.EqualError(t, Rule{Type: "array", MinItems: str("3")}.Valid(items), "must have at least 3 items, got 2")
+	assert.EqualError(t, Rule{Type: "array", MaxItems: str("1")}.Valid(items), "must have at most 1 items, got 2")
+}
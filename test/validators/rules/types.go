@@ -0,0 +1,115 @@
+package rules
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NullValue - значение null из JSON.
+// Валидаторы передают его в правило вместо nil, который означает отсутствие значения.
+type NullValue struct{}
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat проверяет строку на соответствие формату типа правила
+func (r Rule) validFormat(val string) error {
+	switch r.Type {
+	case TypeUUID:
+		if !uuidRe.MatchString(val) {
+			return fmt.Errorf("is not a uuid")
+		}
+	case TypeEmail:
+		addr, err := mail.ParseAddress(val)
+		if err != nil || addr.Name != "" || addr.Address != val {
+			return fmt.Errorf("is not an email")
+		}
+	case TypeURL:
+		u, err := url.Parse(val)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("is not an absolute url")
+		}
+	case TypeIP:
+		if net.ParseIP(val) == nil {
+			return fmt.Errorf("is not an ip address")
+		}
+	case TypeBase64:
+		if !isBase64(val) {
+			return fmt.Errorf("is not a base64")
+		}
+	case TypeDatetime:
+		return r.validDatetime(val)
+	}
+
+	return nil
+}
+
+func isBase64(val string) bool {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if _, err := enc.DecodeString(val); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validDatetime проверяет время в формате RFC3339 и его границы before и after
+func (r Rule) validDatetime(val string) error {
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return fmt.Errorf("is not a datetime in RFC3339")
+	}
+
+	now := time.Now()
+
+	if r.Before != nil {
+		before, err := parseMoment(*r.Before, now)
+		if err != nil {
+			return fmt.Errorf("parsing Before '%s': %w", *r.Before, err)
+		}
+
+		if !t.Before(before) {
+			return fmt.Errorf("must be before '%s'", before.Format(time.RFC3339))
+		}
+	}
+
+	if r.After != nil {
+		after, err := parseMoment(*r.After, now)
+		if err != nil {
+			return fmt.Errorf("parsing After '%s': %w", *r.After, err)
+		}
+
+		if !t.After(after) {
+			return fmt.Errorf("must be after '%s'", after.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// parseMoment разбирает время в RFC3339 или относительно текущего: now, now-5m, now+1h30m
+func parseMoment(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if !strings.HasPrefix(s, "now") {
+		return time.Parse(time.RFC3339Nano, s)
+	}
+
+	offset := strings.TrimSpace(strings.TrimPrefix(s, "now"))
+	if offset == "" {
+		return now, nil
+	}
+
+	d, err := time.ParseDuration(strings.ReplaceAll(offset, " ", ""))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return now.Add(d), nil
+}
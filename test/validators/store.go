@@ -16,10 +16,47 @@ type StoreBase struct {
 	store *store.Store
 }
 
-func (s StoreBase) storeSave(rule rules.Rule, value string) {
+// storeSave сохраняет значение в хранилище, если в правиле указан store
+func (s StoreBase) storeSave(rule rules.Rule, value interface{}) error {
 	if rule.Store != nil {
-		s.store.Set(*rule.Store, value)
+		if err := s.store.Set(*rule.Store, value); err != nil {
+			return fmt.Errorf("store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// storeCaptures сохраняет именованные группы regex правила в хранилище под их именами
+func (s StoreBase) storeCaptures(rule rules.Rule, value interface{}) error {
+	for name, capture := range rule.Captures(value) {
+		if err := s.store.Set(name, capture); err != nil {
+			return fmt.Errorf("store regex group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validClaims проверяет claims JWT токена вложенными правилами как JSON объект
+func (s StoreBase) validClaims(rule rules.Rule, token string) error {
+	if len(rule.Fields) == 0 {
+		return nil
+	}
+
+	claims, err := rules.JWTClaims(token)
+	if err != nil {
+		return err
+	}
+
+	validator := NewJSONValidator(s.store)
+	for _, subRule := range rule.Fields {
+		if err = validator.ValidBody(subRule, claims); err != nil {
+			return fmt.Errorf("jwt claims: %w", err)
+		}
 	}
+
+	return nil
 }
 
 // prepareRule заменяет переменные в правиле
@@ -40,54 +77,86 @@ func (s StoreBase) prepareRule(rule rules.Rule) (rules.Rule, error) {
 	if err != nil {
 		return rule, fmt.Errorf("preparing rule key: %w", err)
 	}
-	if rule.Equal != nil {
-		*rule.Equal, err = s.store.Replace(*rule.Equal)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule equal: %w", err)
-		}
-	}
-	if rule.NotEqual != nil {
-		*rule.NotEqual, err = s.store.Replace(*rule.NotEqual)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule not-equal: %w", err)
+
+	// Значения копируются, чтобы не менять описание теста:
+	// правило проверяется повторно, например в until, и переменные могут измениться
+	for _, f := range []struct {
+		name  string
+		field **string
+	}{
+		{"equal", &rule.Equal},
+		{"not-equal", &rule.NotEqual},
+		{"less", &rule.Less},
+		{"greater", &rule.Greater},
+		{"prefix", &rule.Prefix},
+		{"suffix", &rule.Suffix},
+		{"regex", &rule.Regex},
+		{"contains", &rule.Contains},
+		{"not-contains", &rule.NotContains},
+		{"min-length", &rule.MinLength},
+		{"max-length", &rule.MaxLength},
+		{"before", &rule.Before},
+		{"after", &rule.After},
+		{"count", &rule.Count},
+		{"min-items", &rule.MinItems},
+		{"max-items", &rule.MaxItems},
+		{"json-equal", &rule.JSONEqual},
+		{"store", &rule.Store},
+		{"store-path", &rule.StorePath},
+		{"severity", &rule.Severity},
+	} {
+		if *f.field == nil {
+			continue
 		}
-	}
-	if rule.Less != nil {
-		*rule.Less, err = s.store.Replace(*rule.Less)
+
+		value, err := s.store.Replace(**f.field)
 		if err != nil {
-			return rule, fmt.Errorf("preparing rule less: %w", err)
+			return rule, fmt.Errorf("preparing rule %s: %w", f.name, err)
 		}
+
+		*f.field = &value
 	}
-	if rule.Greater != nil {
-		*rule.Greater, err = s.store.Replace(*rule.Greater)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule greater: %w", err)
-		}
+
+	rule.OneOf, err = s.replaceList(rule.OneOf)
+	if err != nil {
+		return rule, fmt.Errorf("preparing rule one-of: %w", err)
 	}
-	if rule.Prefix != nil {
-		*rule.Prefix, err = s.store.Replace(*rule.Prefix)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule prefix: %w", err)
-		}
+	rule.Between, err = s.replaceList(rule.Between)
+	if err != nil {
+		return rule, fmt.Errorf("preparing rule between: %w", err)
 	}
-	if rule.Suffix != nil {
-		*rule.Suffix, err = s.store.Replace(*rule.Suffix)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule suffix: %w", err)
+	if rule.Verify != nil {
+		// Копия, чтобы не менять описание теста
+		verify := *rule.Verify
+		for name, field := range map[string]*string{
+			"secret":    &verify.Secret,
+			"key-file":  &verify.KeyFile,
+			"jwks-file": &verify.JWKSFile,
+		} {
+			*field, err = s.store.Replace(*field)
+			if err != nil {
+				return rule, fmt.Errorf("preparing rule verify %s: %w", name, err)
+			}
 		}
+		rule.Verify = &verify
 	}
-	if rule.Store != nil {
-		*rule.Store, err = s.store.Replace(*rule.Store)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule store: %w", err)
-		}
+
+	return rule, nil
+}
+
+// replaceList заменяет переменные в каждом значении списка, исходный список не меняется
+func (s StoreBase) replaceList(values []string) ([]string, error) {
+	if values == nil {
+		return nil, nil
 	}
-	if rule.Severity != nil {
-		*rule.Severity, err = s.store.Replace(*rule.Severity)
-		if err != nil {
-			return rule, fmt.Errorf("preparing rule severity: %w", err)
+
+	replaced := make([]string, len(values))
+	for i, value := range values {
+		var err error
+		if replaced[i], err = s.store.Replace(value); err != nil {
+			return nil, err
 		}
 	}
 
-	return rule, nil
+	return replaced, nil
 }
@@ -0,0 +1,37 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestPrepareRuleKeepsDescription(t *testing.T) {
+	equal := "{{.status}}"
+	storeName := "{{.name}}"
+	severity := "{{.severity}}"
+	rule := rules.Rule{Key: "status", Equal: &equal, Store: &storeName, Severity: &severity}
+
+	s := store.NewStore(map[string]interface{}{"status": "new", "name": "first", "severity": "warning"})
+	base := StoreBase{store: s}
+
+	prepared, err := base.prepareRule(rule)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", *prepared.Equal)
+	assert.Equal(t, "first", *prepared.Store)
+	assert.Equal(t, "warning", *prepared.Severity)
+
+	// Описание теста не изменилось, поэтому при повторной проверке подставляются новые значения
+	assert.Equal(t, "{{.status}}", equal)
+	assert.Equal(t, "{{.name}}", storeName)
+	assert.Equal(t, "{{.severity}}", severity)
+
+	assert.Nil(t, s.Set("status", "done"))
+
+	prepared, err = base.prepareRule(rule)
+	assert.Nil(t, err)
+	assert.Equal(t, "done", *prepared.Equal)
+}
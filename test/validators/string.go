@@ -1,6 +1,8 @@
 package validators
 
 import (
+	"strings"
+
 	"github.com/MashinaMashina/api-tests/store"
 	"github.com/MashinaMashina/api-tests/test/validators/rules"
 )
@@ -26,10 +28,24 @@ func (s *String) ValidBody(rule rules.Rule, body []byte) error {
 	}
 
 	val := string(body)
-	err = rule.Valid(val)
+
+	// Тело "null" - это null, а не строка
+	if rule.Type == rules.TypeNull && strings.TrimSpace(val) == "null" {
+		err = rule.Valid(rules.NullValue{})
+	} else {
+		err = rule.Valid(val)
+	}
+
+	if err == nil {
+		err = s.storeSave(rule, val)
+	}
 
 	if err == nil {
-		s.storeSave(rule, val)
+		err = s.storeCaptures(rule, val)
+	}
+
+	if err == nil && rule.Type == rules.TypeJWT {
+		err = s.validClaims(rule, val)
 	}
 
 	return err
@@ -8,8 +8,23 @@ import (
 
 // ValidatorDescr - описание валидатора из yaml файла
 type ValidatorDescr struct {
-	Type  string       `yaml:"type"`
-	Rules []rules.Rule `yaml:"rules"`
+	Type  string       `yaml:"type,omitempty"`
+	Rules []rules.Rule `yaml:"rules,omitempty"`
+	// Schema и SchemaFile - JSON Schema для валидатора jsonschema: описание в yaml или путь к файлу
+	Schema     interface{} `yaml:"schema,omitempty"`
+	SchemaFile string      `yaml:"schema-file,omitempty"`
+	// Delimiter - разделитель колонок для валидатора csv, по умолчанию запятая
+	Delimiter string `yaml:"delimiter,omitempty"`
+}
+
+// ValidationRules возвращает правила валидатора.
+// Валидатор jsonschema без правил проверяет весь документ.
+func (d ValidatorDescr) ValidationRules() []rules.Rule {
+	if len(d.Rules) == 0 && d.Type == "jsonschema" {
+		return []rules.Rule{{}}
+	}
+
+	return d.Rules
 }
 
 type BodyValidator interface {
@@ -0,0 +1,49 @@
+package validators
+
+import (
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+	"github.com/MashinaMashina/api-tests/test/validators/xpath"
+)
+
+type XML struct {
+	StoreBase
+}
+
+// NewXMLValidator возвращает валидатор для XML.
+// Ключ правила - выражение XPath, без ключа проверяется весь ответ как строка.
+func NewXMLValidator(store *store.Store) *XML {
+	return &XML{
+		StoreBase{store: store},
+	}
+}
+
+func (x *XML) ValidBody(rule rules.Rule, body []byte) error {
+	var err error
+	rule, err = x.prepareRule(rule)
+
+	if err != nil {
+		return err
+	}
+
+	if rule.Key == "" {
+		return x.validFound(rule, []found{{value: string(body)}})
+	}
+
+	path, err := xpath.Parse(rule.Key)
+	if err != nil {
+		return err
+	}
+
+	doc, err := xpath.ParseDocument(body)
+	if err != nil {
+		return err
+	}
+
+	var values []found
+	for _, m := range path.Find(doc) {
+		values = append(values, found{path: m.Path, value: m.Value})
+	}
+
+	return x.validFound(rule, values)
+}
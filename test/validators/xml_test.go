@@ -0,0 +1,34 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestXML(t *testing.T) {
+	varTrue := true
+	var2 := "2"
+	var42 := "42"
+	varSum := "10.5"
+	varRoman := "Roman"
+
+	validator := NewXMLValidator(store.NewStore(nil))
+	body := []byte(`<report id="42"><row id="1"><name>Ivan</name><sum>10.5</sum></row><row id="2"><name>Roman</name><sum>3</sum></row></report>`)
+
+	checkBodyCases(t, validator, body, []bodyCase{
+		{Name: "Атрибут", rule: rules.Rule{Type: "integer", Key: "/report/@id", Equal: &var42}},
+		{Name: "Количество элементов", rule: rules.Rule{Type: "array", Key: "//row", Count: &var2}},
+		{Name: "Функция XPath", rule: rules.Rule{Type: "integer", Key: "count(//row)", Equal: &var2}},
+		{Name: "Элемент по условию", rule: rules.Rule{Key: "//row[@id='2']/name", Equal: &varRoman}},
+		{Name: "Число в каждом элементе", rule: rules.Rule{Type: "float", Key: "//sum", Less: &varSum}, expectErr: "field '/report/row[1]/sum': must less then '10.5'"},
+		{Name: "Отсутствующий элемент", rule: rules.Rule{Key: "//total", Absent: &varTrue}},
+		{Name: "Обязательный элемент", rule: rules.Rule{Key: "//total"}, expectErr: "field '//total': required not exists"},
+		{Name: "Неверное выражение", rule: rules.Rule{Key: "//row[@id=1x]"}, expectErr: "parsing xpath '//row[@id=1x]': //row[@id=1x] has an invalid token"},
+	})
+
+	checkBodyCases(t, validator, []byte("<report><row>"), []bodyCase{
+		{Name: "Неверный XML", rule: rules.Rule{Key: "//row"}, expectErr: "parsing xml: XML syntax error on line 1: unexpected EOF"},
+	})
+}
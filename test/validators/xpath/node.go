@@ -0,0 +1,124 @@
+package xpath
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node - узел XML документа: элемент или текст.
+// Корень дерева - документ без имени, его единственный элемент - корневой элемент XML.
+type Node struct {
+	Name string
+	// Data - текст узла, только для текстовых узлов
+	Data     string
+	Attrs    []xml.Attr
+	Children []*Node
+	Parent   *Node
+	// index - номер узла среди детей родителя
+	index int
+}
+
+// ParseDocument разбирает XML документ. Пространства имен не учитываются, используются локальные имена.
+func ParseDocument(data []byte) (*Node, error) {
+	doc := &Node{}
+	current := doc
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing xml: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &Node{Name: t.Name.Local}
+			for _, attr := range t.Attr {
+				attr.Name.Space = ""
+				node.Attrs = append(node.Attrs, attr)
+			}
+
+			current.append(node)
+			current = node
+		case xml.EndElement:
+			if current.Parent != nil {
+				current = current.Parent
+			}
+		case xml.CharData:
+			// Текст вне корневого элемента не относится к документу
+			if current == doc {
+				continue
+			}
+
+			if last := len(current.Children) - 1; last >= 0 && current.Children[last].isText() {
+				current.Children[last].Data += string(t)
+				continue
+			}
+
+			current.append(&Node{Data: string(t)})
+		}
+	}
+
+	if len(doc.Children) == 0 {
+		return nil, fmt.Errorf("parsing xml: no root element")
+	}
+
+	return doc, nil
+}
+
+func (n *Node) append(child *Node) {
+	child.Parent = n
+	child.index = len(n.Children)
+	n.Children = append(n.Children, child)
+}
+
+func (n *Node) isText() bool {
+	return n.Name == "" && n.Parent != nil
+}
+
+// Text возвращает весь текст узла вместе с вложенными элементами
+func (n *Node) Text() string {
+	var sb strings.Builder
+	n.writeText(&sb)
+
+	return strings.TrimSpace(sb.String())
+}
+
+func (n *Node) writeText(sb *strings.Builder) {
+	sb.WriteString(n.Data)
+	for _, child := range n.Children {
+		child.writeText(sb)
+	}
+}
+
+// path возвращает путь к элементу, например /report/rows/row[2].
+// Номер указывается, если у родителя несколько элементов с таким именем.
+func (n *Node) path() string {
+	if n.Parent == nil {
+		return ""
+	}
+
+	position, total := 0, 0
+	for _, sibling := range n.Parent.Children {
+		if sibling.Name == n.Name {
+			total++
+			if sibling == n {
+				position = total
+			}
+		}
+	}
+
+	if total > 1 {
+		return fmt.Sprintf("%s/%s[%d]", n.Parent.path(), n.Name, position)
+	}
+
+	return n.Parent.path() + "/" + n.Name
+}
@@ -0,0 +1,203 @@
+// Package xpath ищет значения в XML по выражениям XPath 1.0.
+//
+// Выражения разбирает и выполняет библиотека antchfx/xpath: пути и оси, предикаты,
+// функции (count, contains, starts-with, normalize-space и другие), операторы и объединение |.
+// Пространства имен не учитываются: элементы ищутся по локальным именам.
+package xpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/xpath"
+)
+
+// Match - найденное значение и конкретный путь к нему, например /report/rows/row[2]/@id.
+// Для выражений, результат которых не узлы, например count(//row), путь - само выражение.
+type Match struct {
+	Path  string
+	Value string
+}
+
+// Path - разобранное выражение XPath
+type Path struct {
+	expr *xpath.Expr
+}
+
+// Parse разбирает выражение XPath
+func Parse(expr string) (*Path, error) {
+	compiled, err := xpath.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing xpath '%s': %w", expr, err)
+	}
+
+	return &Path{expr: compiled}, nil
+}
+
+// Find возвращает все значения документа, подходящие под путь.
+// Значение элемента - весь его текст вместе с вложенными элементами,
+// текстовые узлы из одних пробелов пропускаются.
+func (p *Path) Find(doc *Node) []Match {
+	switch v := p.expr.Evaluate(newNavigator(doc)).(type) {
+	case *xpath.NodeIterator:
+		var res []Match
+		for v.MoveNext() {
+			if m, ok := v.Current().(*navigator).match(); ok {
+				res = append(res, m)
+			}
+		}
+		return res
+	case float64:
+		return []Match{{Path: p.expr.String(), Value: strconv.FormatFloat(v, 'f', -1, 64)}}
+	case string:
+		return []Match{{Path: p.expr.String(), Value: v}}
+	case bool:
+		return []Match{{Path: p.expr.String(), Value: strconv.FormatBool(v)}}
+	}
+
+	return nil
+}
+
+// navigator - курсор по дереву Node для библиотеки xpath
+type navigator struct {
+	root *Node
+	curr *Node
+	// attr - номер атрибута, на котором стоит курсор, или -1
+	attr int
+}
+
+func newNavigator(doc *Node) *navigator {
+	return &navigator{root: doc, curr: doc, attr: -1}
+}
+
+// match возвращает найденное значение с путем к нему
+func (n *navigator) match() (Match, bool) {
+	switch {
+	case n.attr >= 0:
+		attr := n.curr.Attrs[n.attr]
+		return Match{Path: n.curr.path() + "/@" + attr.Name.Local, Value: attr.Value}, true
+	case n.curr.isText():
+		text := n.curr.Text()
+		return Match{Path: n.curr.Parent.path() + "/text()", Value: text}, text != ""
+	case n.curr == n.root:
+		return Match{Path: "/", Value: n.curr.Text()}, true
+	}
+
+	return Match{Path: n.curr.path(), Value: n.curr.Text()}, true
+}
+
+func (n *navigator) NodeType() xpath.NodeType {
+	switch {
+	case n.attr >= 0:
+		return xpath.AttributeNode
+	case n.curr == n.root:
+		return xpath.RootNode
+	case n.curr.isText():
+		return xpath.TextNode
+	}
+
+	return xpath.ElementNode
+}
+
+func (n *navigator) LocalName() string {
+	if n.attr >= 0 {
+		return n.curr.Attrs[n.attr].Name.Local
+	}
+
+	return n.curr.Name
+}
+
+func (n *navigator) Prefix() string {
+	return ""
+}
+
+func (n *navigator) Value() string {
+	if n.attr >= 0 {
+		return n.curr.Attrs[n.attr].Value
+	}
+
+	var sb strings.Builder
+	n.curr.writeText(&sb)
+
+	return sb.String()
+}
+
+func (n *navigator) Copy() xpath.NodeNavigator {
+	c := *n
+	return &c
+}
+
+func (n *navigator) MoveToRoot() {
+	n.curr = n.root
+	n.attr = -1
+}
+
+func (n *navigator) MoveToParent() bool {
+	if n.attr >= 0 {
+		n.attr = -1
+		return true
+	}
+	if n.curr.Parent == nil {
+		return false
+	}
+
+	n.curr = n.curr.Parent
+	return true
+}
+
+func (n *navigator) MoveToNextAttribute() bool {
+	if n.attr+1 >= len(n.curr.Attrs) {
+		return false
+	}
+
+	n.attr++
+	return true
+}
+
+func (n *navigator) MoveToChild() bool {
+	if n.attr >= 0 || len(n.curr.Children) == 0 {
+		return false
+	}
+
+	n.curr = n.curr.Children[0]
+	return true
+}
+
+func (n *navigator) MoveToFirst() bool {
+	if n.attr >= 0 || n.curr.Parent == nil {
+		return false
+	}
+
+	n.curr = n.curr.Parent.Children[0]
+	return true
+}
+
+func (n *navigator) MoveToNext() bool {
+	if n.attr >= 0 || n.curr.Parent == nil || n.curr.index+1 >= len(n.curr.Parent.Children) {
+		return false
+	}
+
+	n.curr = n.curr.Parent.Children[n.curr.index+1]
+	return true
+}
+
+func (n *navigator) MoveToPrevious() bool {
+	if n.attr >= 0 || n.curr.Parent == nil || n.curr.index == 0 {
+		return false
+	}
+
+	n.curr = n.curr.Parent.Children[n.curr.index-1]
+	return true
+}
+
+func (n *navigator) MoveTo(other xpath.NodeNavigator) bool {
+	o, ok := other.(*navigator)
+	if !ok || o.root != n.root {
+		return false
+	}
+
+	n.curr = o.curr
+	n.attr = o.attr
+	return true
+}
@@ -0,0 +1,132 @@
+package xpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `<?xml version="1.0" encoding="UTF-8"?>
+<report xmlns:r="urn:report" id="42">
+  <title>Заправки</title>
+  <rows>
+    <row id="1"><name>Ivan</name><sum>10.5</sum></row>
+    <row id="2" status="closed"><name>Roman</name><sum>3</sum></row>
+    <row id="3"><name> Anna  Maria </name><sum>7</sum><note>Первая<b>часть</b>вторая</note></row>
+  </rows>
+  <r:total>20.5</r:total>
+  <!-- комментарий -->
+</report>`
+
+func TestFind(t *testing.T) {
+	doc, err := ParseDocument([]byte(document))
+	assert.Nil(t, err)
+
+	cases := []struct {
+		Path   string
+		Expect []Match
+	}{
+		// Пути
+		{Path: "/report/title", Expect: []Match{{Path: "/report/title", Value: "Заправки"}}},
+		{Path: "report/title", Expect: []Match{{Path: "/report/title", Value: "Заправки"}}},
+		{Path: "//row[2]/name", Expect: []Match{{Path: "/report/rows/row[2]/name", Value: "Roman"}}},
+		{Path: "/report/*[1]", Expect: []Match{{Path: "/report/title", Value: "Заправки"}}},
+		{Path: "/report/rows/row[1]", Expect: []Match{{Path: "/report/rows/row[1]", Value: "Ivan10.5"}}},
+		{Path: "/report/rows/row[4]", Expect: nil},
+		{Path: "//missing", Expect: nil},
+
+		// Атрибуты
+		{Path: "report/@id", Expect: []Match{{Path: "/report/@id", Value: "42"}}},
+		{Path: "//row/@id", Expect: []Match{
+			{Path: "/report/rows/row[1]/@id", Value: "1"},
+			{Path: "/report/rows/row[2]/@id", Value: "2"},
+			{Path: "/report/rows/row[3]/@id", Value: "3"},
+		}},
+		{Path: "//row[2]/@*", Expect: []Match{
+			{Path: "/report/rows/row[2]/@id", Value: "2"},
+			{Path: "/report/rows/row[2]/@status", Value: "closed"},
+		}},
+
+		// Текст
+		{Path: "//row[last()]/sum/text()", Expect: []Match{{Path: "/report/rows/row[3]/sum/text()", Value: "7"}}},
+		{Path: "//note/text()", Expect: []Match{
+			{Path: "/report/rows/row[3]/note/text()", Value: "Первая"},
+			{Path: "/report/rows/row[3]/note/text()", Value: "вторая"},
+		}},
+		{Path: "//note", Expect: []Match{{Path: "/report/rows/row[3]/note", Value: "Перваячастьвторая"}}},
+
+		// Предикаты
+		{Path: "//row[@status='closed']/name", Expect: []Match{{Path: "/report/rows/row[2]/name", Value: "Roman"}}},
+		{Path: "//row[@status]/@id", Expect: []Match{{Path: "/report/rows/row[2]/@id", Value: "2"}}},
+		{Path: "//row[not(@status)]/@id", Expect: []Match{
+			{Path: "/report/rows/row[1]/@id", Value: "1"},
+			{Path: "/report/rows/row[3]/@id", Value: "3"},
+		}},
+		{Path: "//row[name='Ivan']/sum", Expect: []Match{{Path: "/report/rows/row[1]/sum", Value: "10.5"}}},
+		{Path: "//row[sum > 5 and sum < 10]/@id", Expect: []Match{{Path: "/report/rows/row[3]/@id", Value: "3"}}},
+		{Path: "//row[note]/@id", Expect: []Match{{Path: "/report/rows/row[3]/@id", Value: "3"}}},
+		{Path: "//row[position() > 1]/@id", Expect: []Match{
+			{Path: "/report/rows/row[2]/@id", Value: "2"},
+			{Path: "/report/rows/row[3]/@id", Value: "3"},
+		}},
+		{Path: "//row[contains(name, 'om')]/@id", Expect: []Match{{Path: "/report/rows/row[2]/@id", Value: "2"}}},
+		{Path: "//row[starts-with(name, 'I')]/@id", Expect: []Match{{Path: "/report/rows/row[1]/@id", Value: "1"}}},
+		{Path: "//row[normalize-space(name)='Anna Maria']/@id", Expect: []Match{{Path: "/report/rows/row[3]/@id", Value: "3"}}},
+
+		// Оси
+		{Path: "//name[text()='Ivan']/../@id", Expect: []Match{{Path: "/report/rows/row[1]/@id", Value: "1"}}},
+		{Path: "//row[1]/following-sibling::row/@id", Expect: []Match{
+			{Path: "/report/rows/row[2]/@id", Value: "2"},
+			{Path: "/report/rows/row[3]/@id", Value: "3"},
+		}},
+		{Path: "//row[3]/preceding-sibling::row[1]/@id", Expect: []Match{{Path: "/report/rows/row[2]/@id", Value: "2"}}},
+		{Path: "//b/ancestor::row/@id", Expect: []Match{{Path: "/report/rows/row[3]/@id", Value: "3"}}},
+		{Path: "//title | //row[1]/name", Expect: []Match{
+			{Path: "/report/title", Value: "Заправки"},
+			{Path: "/report/rows/row[1]/name", Value: "Ivan"},
+		}},
+
+		// Пространства имен не учитываются
+		{Path: "/report/total", Expect: []Match{{Path: "/report/total", Value: "20.5"}}},
+
+		// Выражения, результат которых не узлы
+		{Path: "count(//row)", Expect: []Match{{Path: "count(//row)", Value: "3"}}},
+		{Path: "sum(//row/sum)", Expect: []Match{{Path: "sum(//row/sum)", Value: "20.5"}}},
+		{Path: "string(//row[2]/name)", Expect: []Match{{Path: "string(//row[2]/name)", Value: "Roman"}}},
+		{Path: "count(//row) = 3", Expect: []Match{{Path: "count(//row) = 3", Value: "true"}}},
+	}
+
+	for _, c := range cases {
+		path, err := Parse(c.Path)
+		if !assert.Nil(t, err, c.Path) {
+			continue
+		}
+
+		assert.Equal(t, c.Expect, path.Find(doc), c.Path)
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	_, err := ParseDocument([]byte("<report><row>"))
+	assert.EqualError(t, err, "parsing xml: XML syntax error on line 1: unexpected EOF")
+
+	_, err = ParseDocument([]byte("   "))
+	assert.EqualError(t, err, "parsing xml: no root element")
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		Path      string
+		ExpectErr string
+	}{
+		{Path: "", ExpectErr: "parsing xpath '': expr expression is nil"},
+		{Path: "//row[", ExpectErr: "parsing xpath '//row[': expression must evaluate to a node-set"},
+		{Path: "count(", ExpectErr: "parsing xpath 'count(': expression must evaluate to a node-set"},
+		{Path: "//row[@id=1x]", ExpectErr: "parsing xpath '//row[@id=1x]': //row[@id=1x] has an invalid token"},
+	}
+
+	for _, c := range cases {
+		_, err := Parse(c.Path)
+		assert.EqualError(t, err, c.ExpectErr, c.Path)
+	}
+}
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+type YAML struct {
+	json *JSON
+}
+
+// NewYAMLValidator возвращает валидатор для YAML.
+// Ответ приводится к JSON, правила работают так же, как в валидаторе json.
+func NewYAMLValidator(store *store.Store) *YAML {
+	return &YAML{
+		json: NewJSONValidator(store),
+	}
+}
+
+func (y *YAML) ValidBody(rule rules.Rule, body []byte) error {
+	var data interface{}
+	if err := yaml.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("parsing yaml: %w", err)
+	}
+
+	converted, err := json.Marshal(yamlToJSON(data))
+	if err != nil {
+		return fmt.Errorf("converting yaml to json: %w", err)
+	}
+
+	return y.json.ValidBody(rule, converted)
+}
+
+// yamlToJSON приводит ключи объектов к строкам, иначе JSON их не закодирует
+func yamlToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = yamlToJSON(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			res[fmt.Sprint(key)] = yamlToJSON(item)
+		}
+		return res
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlToJSON(item)
+		}
+		return v
+	}
+
+	return value
+}
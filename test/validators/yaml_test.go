@@ -0,0 +1,29 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/MashinaMashina/api-tests/store"
+	"github.com/MashinaMashina/api-tests/test/validators/rules"
+)
+
+func TestYAML(t *testing.T) {
+	var2 := "2"
+	varApp := "app"
+	varOne := "one"
+
+	validator := NewYAMLValidator(store.NewStore(nil))
+	body := []byte("name: app\n1: one\nreplicas: 2\nports: [80, 443]\n")
+
+	checkBodyCases(t, validator, body, []bodyCase{
+		{Name: "Строка", rule: rules.Rule{Key: "name", Equal: &varApp}},
+		{Name: "Число", rule: rules.Rule{Type: "integer", Key: "replicas", Equal: &var2}},
+		{Name: "Массив", rule: rules.Rule{Type: "array", Key: "ports", Count: &var2}},
+		{Name: "Нестроковый ключ", rule: rules.Rule{Key: "1", Equal: &varOne}},
+		{Name: "Отсутствующее поле", rule: rules.Rule{Key: "image"}, expectErr: "field 'image': required not exists"},
+	})
+
+	checkBodyCases(t, validator, []byte("name: [app"), []bodyCase{
+		{Name: "Неверный YAML", rule: rules.Rule{Key: "name"}, expectErr: "parsing yaml: yaml: line 1: did not find expected ',' or ']'"},
+	})
+}
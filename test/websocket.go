@@ -9,6 +9,8 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog"
+
+	"github.com/MashinaMashina/api-tests/test/validators"
 )
 
 const (
@@ -16,29 +18,48 @@ const (
 	ConnClosed int32 = 0
 )
 
+// wsCloseTimeout - сколько ждать ответного закрытия соединения от сервера
+const wsCloseTimeout = time.Second
+
 type wsConnect struct {
 	cancel   context.CancelFunc
 	status   int32 // 0 - connection already closed, 1 - opened
 	messages chan []byte
+	conn     *websocket.Conn
+	done     chan struct{} // закрывается, когда горутина чтения завершилась
+}
+
+// close закрывает соединение и ждет завершения горутины чтения,
+// чтобы после закрытия она больше ничего не писала в лог группы
+func (c *wsConnect) close() {
+	c.cancel()
+
+	select {
+	case <-c.done:
+	case <-time.After(wsCloseTimeout):
+	}
+
+	_ = c.conn.Close()
+	<-c.done
 }
 
 // receive ожидает получения сообщения из websocket соединения по фильтру
 func (r *RunnerGroup) receive(logger zerolog.Logger, rec Receive) ([]byte, bool) {
 	if rec.Channel == "" {
-		return nil, r.error(logger, fmt.Errorf("empty receive channel name"))
+		return nil, r.errored(logger, fmt.Errorf("empty receive channel name"))
 	}
 
 	logger = logger.With().Str("channel", rec.Channel).Logger()
 
 	connection, ok := r.wsConnections[rec.Channel]
 	if !ok {
-		return nil, r.error(logger, fmt.Errorf("not found connection"))
+		return nil, r.errored(logger, fmt.Errorf("not found connection"))
 	}
 
 	timeout, err := r.timeout(rec.Timeout)
 
 	if err != nil {
-		return nil, r.error(logger, fmt.Errorf("preparing timeout: %w", err))
+		return nil, r.errored(logger, fmt.Errorf("preparing timeout: %w", err))
 	}
 
 	logger = logger.With().Str("timeout", timeout.String()).Logger()
@@ -57,38 +78,58 @@ func (r *RunnerGroup) receive(logger zerolog.Logger, rec Receive) ([]byte, bool)
 				return nil, r.error(logger, fmt.Errorf("connection '%s' closed", rec.Channel))
 			}
 
-			// Отключенный логгер, чтобы фильтр не писал лог
-			fakeLogger := logger.With().Logger().Level(zerolog.Disabled)
-			if r.validBody(fakeLogger, rec.Filter, msg) {
+			if r.matchFilter(logger, rec.Filter, msg) {
 				return msg, true
 			}
 		}
 	}
 }
 
+// matchFilter проверяет сообщение фильтром.
+// Неподходящее сообщение не является ошибкой, поэтому проверка
+// не пишет лог и не влияет на результат теста.
+func (r *RunnerGroup) matchFilter(logger zerolog.Logger, filter []validators.ValidatorDescr, msg []byte) bool {
+	ok, _ := r.quiet(logger, func(logger zerolog.Logger) bool {
+		return r.validBody(logger, filter, msg)
+	})
+
+	return ok
+}
+
 // wsRequest создает websocket соединение
 func (r *RunnerGroup) wsRequest(logger zerolog.Logger, req Request) (*http.Response, bool) {
 	if req.Channel == "" {
-		return nil, r.error(logger, fmt.Errorf("empty websocket channel name"))
+		return nil, r.errored(logger, fmt.Errorf("empty websocket channel name"))
 	}
 
 	// Закрываем, если соединение с таким именем уже было
 	if channel, exists := r.wsConnections[req.Channel]; exists {
-		channel.cancel()
+		channel.close()
 	}
 
 	url, err := r.store.Replace(req.URL)
 
 	if err != nil {
-		return nil, r.error(logger, fmt.Errorf("preparing url: %w", err))
+		return nil, r.errored(logger, fmt.Errorf("preparing url: %w", err))
 	}
 
 	logger = logger.With().Str("url", url).Logger()
 
+	if r.result != nil {
+		r.result.Request = &RequestDetails{
+			Method: http.MethodGet,
+			URL:    url,
+		}
+	}
+
+	// Для websocket соединения известно только общее время открытия
+	timings := newTimings()
 	c, resp, err := websocket.DefaultDialer.Dial(url, nil)
 	if err != nil {
 		return nil, r.error(logger, fmt.Errorf("open websocket connect: %w", err))
 	}
+	timings.finish()
+	r.timings = timings
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -96,10 +137,13 @@ func (r *RunnerGroup) wsRequest(logger zerolog.Logger, req Request) (*http.Respo
 		cancel:   cancel,
 		messages: make(chan []byte, 256),
 		status:   ConnOpened,
+		conn:     c,
+		done:     make(chan struct{}),
 	}
 
 	// read соединение
 	go func() {
+		defer close(connection.done)
 		defer close(connection.messages)
 
 		for {
@@ -112,7 +156,12 @@ func (r *RunnerGroup) wsRequest(logger zerolog.Logger, req Request) (*http.Respo
 				return
 			}
 
-			connection.messages <- message
+			select {
+			case connection.messages <- message:
+			case <-ctx.Done():
+				// Сообщения закрытого соединения уже никто не прочитает
+				return
+			}
 			logger.Info().Msgf("recv: %s", message)
 		}
 	}()
@@ -132,8 +181,8 @@ func (r *RunnerGroup) wsRequest(logger zerolog.Logger, req Request) (*http.Respo
 				err = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 				if err != nil && err.Error() != "websocket: close sent" {
 					logger.Error().Err(err).Msg("closing websocket")
-					return
 				}
+				return
 
 			// ping
 			case t := <-ticker.C: